@@ -0,0 +1,93 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+// Package audit hashes and verifies the audit log written by the file audit
+// sink of the beer likes server.
+//
+// The log holds one AuditRecord per line in the JSON protobuf mapping. Each
+// record has the hash of the previous record and its own hash, so a record
+// that is changed, removed or inserted breaks the chain. The first record has
+// sequence 1 and no previous hash, so removing the first records breaks it too:
+//
+//	n, last, err := audit.Verify(file)
+//
+// Removing the last records leaves a valid chain. To detect it, keep the
+// sequence and hash of the last record somewhere else, e.g. from a stream
+// sink, and compare them with the last record returned by Verify.
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// Hash returns the hex SHA-256 of the deterministic protobuf encoding of a
+// record without its hash. The JSON of the log is not hashed, since its
+// formatting is not canonical and may change with the protobuf library.
+func Hash(record *pb.AuditRecord) (string, error) {
+	unhashed := proto.Clone(record).(*pb.AuditRecord)
+	unhashed.Hash = ""
+	var b proto.Buffer
+	b.SetDeterministic(true)
+	if err := b.Marshal(unhashed); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b.Bytes())
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Marshal returns the line of a record in the audit log, without the newline.
+func Marshal(record *pb.AuditRecord) (string, error) {
+	return (&jsonpb.Marshaler{}).MarshalToString(record)
+}
+
+// Verify reads an audit log and checks the hash of every record, that it has
+// the hash of the record before it and that the sequences increase by one from
+// a first record of sequence 1 without a previous hash. It returns the number
+// of records and the last record, or nil if there are none. It cannot tell
+// whether records were removed from the end of the log.
+func Verify(r io.Reader) (int, *pb.AuditRecord, error) {
+	reader := bufio.NewReader(r)
+	var last *pb.AuditRecord
+	for n := 0; ; n++ {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF && line == "" {
+			return n, last, nil
+		}
+		if err != nil && err != io.EOF {
+			return n, last, err
+		}
+		record := &pb.AuditRecord{}
+		if err := jsonpb.UnmarshalString(line, record); err != nil {
+			return n, last, fmt.Errorf("line %d: %v", n+1, err)
+		}
+		hash, err := Hash(record)
+		if err != nil {
+			return n, last, fmt.Errorf("line %d: %v", n+1, err)
+		}
+		if hash != record.Hash {
+			return n, last, fmt.Errorf("line %d: the record was changed: its hash is %s, not %s", n+1, hash, record.Hash)
+		}
+		if last == nil && (record.PreviousHash != "" || record.Sequence != 1) {
+			return n, last, fmt.Errorf("line %d: the records before it were removed: the first record has sequence %d and previous hash %q, not 1 and none", n+1, record.Sequence, record.PreviousHash)
+		}
+		if last != nil && record.PreviousHash != last.Hash {
+			return n, last, fmt.Errorf("line %d: the record before it was changed or removed: its previous hash is %s, not %s", n+1, record.PreviousHash, last.Hash)
+		}
+		if last != nil && record.Sequence != last.Sequence+1 {
+			return n, last, fmt.Errorf("line %d: sequence %d does not follow %d", n+1, record.Sequence, last.Sequence)
+		}
+		last = record
+	}
+}
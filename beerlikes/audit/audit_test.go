@@ -0,0 +1,112 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package audit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// chain returns n chained records, as the file audit sink writes them.
+func chain(t *testing.T, n int) []*pb.AuditRecord {
+	var records []*pb.AuditRecord
+	previous := ""
+	for i := 1; i <= n; i++ {
+		record := &pb.AuditRecord{Sequence: uint64(i), Principal: "alice", Method: "/beerlikes.BeerLikes/CreateLike", PreviousHash: previous}
+		hash, err := Hash(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record.Hash = hash
+		previous = hash
+		records = append(records, record)
+	}
+	return records
+}
+
+// auditLog returns the audit log of records.
+func auditLog(t *testing.T, records []*pb.AuditRecord) string {
+	var lines []string
+	for _, record := range records {
+		line, err := Marshal(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line+"\n")
+	}
+	return strings.Join(lines, "")
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(records []*pb.AuditRecord) []*pb.AuditRecord
+		n    int    // records verified
+		last uint64 // sequence of the last verified record
+		err  string // part of the error, if any
+	}{
+		{"intact", func(records []*pb.AuditRecord) []*pb.AuditRecord {
+			return records
+		}, 4, 4, ""},
+		{"empty", func(records []*pb.AuditRecord) []*pb.AuditRecord {
+			return nil
+		}, 0, 0, ""},
+		{"altered", func(records []*pb.AuditRecord) []*pb.AuditRecord {
+			records[1].Principal = "mallory"
+			return records
+		}, 1, 1, "line 2: the record was changed"},
+		{"altered and rehashed", func(records []*pb.AuditRecord) []*pb.AuditRecord {
+			records[1].Principal = "mallory"
+			records[1].Hash, _ = Hash(records[1])
+			return records
+		}, 2, 2, "line 3: the record before it was changed or removed"},
+		{"removed", func(records []*pb.AuditRecord) []*pb.AuditRecord {
+			return append(records[:1], records[2:]...)
+		}, 1, 1, "line 2: the record before it was changed or removed"},
+		{"reordered", func(records []*pb.AuditRecord) []*pb.AuditRecord {
+			records[1], records[2] = records[2], records[1]
+			return records
+		}, 1, 1, "line 2: the record before it was changed or removed"},
+		{"head truncated", func(records []*pb.AuditRecord) []*pb.AuditRecord {
+			return records[1:]
+		}, 0, 0, "line 1: the records before it were removed"},
+		{"first record without a previous hash after the head", func(records []*pb.AuditRecord) []*pb.AuditRecord {
+			head := chain(t, 1)[0]
+			head.Sequence = 2
+			head.Hash, _ = Hash(head)
+			return []*pb.AuditRecord{head}
+		}, 0, 0, "line 1: the records before it were removed"},
+		// Removing the last records leaves a valid chain, which only a copy
+		// of the last sequence and hash kept elsewhere can tell.
+		{"tail truncated", func(records []*pb.AuditRecord) []*pb.AuditRecord {
+			return records[:3]
+		}, 3, 3, ""},
+	}
+	for _, test := range tests {
+		records := test.edit(chain(t, 4))
+		n, last, err := Verify(strings.NewReader(auditLog(t, records)))
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: Verify returned %v", test.name, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%s: Verify returned %v, want an error with %q", test.name, err, test.err)
+		}
+		if n != test.n {
+			t.Errorf("%s: Verify verified %d records, want %d", test.name, n, test.n)
+		}
+		if got := last.GetSequence(); got != test.last {
+			t.Errorf("%s: the last verified record is %d, want %d", test.name, got, test.last)
+		}
+		if last != nil && !proto.Equal(last, records[test.last-1]) {
+			t.Errorf("%s: the last verified record is %v, want %v", test.name, last, records[test.last-1])
+		}
+	}
+}
@@ -6,6 +6,8 @@ package beerlikes
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import field_mask "google.golang.org/genproto/protobuf/field_mask"
+import timestamp "github.com/golang/protobuf/ptypes/timestamp"
 
 import (
 	context "golang.org/x/net/context"
@@ -23,6 +25,215 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type ToggleLikeRequest_Target int32
+
+const (
+	ToggleLikeRequest_TOGGLE   ToggleLikeRequest_Target = 0
+	ToggleLikeRequest_LIKED    ToggleLikeRequest_Target = 1
+	ToggleLikeRequest_DISLIKED ToggleLikeRequest_Target = 2
+	ToggleLikeRequest_CLEARED  ToggleLikeRequest_Target = 3
+)
+
+var ToggleLikeRequest_Target_name = map[int32]string{
+	0: "TOGGLE",
+	1: "LIKED",
+	2: "DISLIKED",
+	3: "CLEARED",
+}
+
+var ToggleLikeRequest_Target_value = map[string]int32{
+	"TOGGLE":   0,
+	"LIKED":    1,
+	"DISLIKED": 2,
+	"CLEARED":  3,
+}
+
+func (x ToggleLikeRequest_Target) String() string {
+	return proto.EnumName(ToggleLikeRequest_Target_name, int32(x))
+}
+
+func (ToggleLikeRequest_Target) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{6, 0}
+}
+
+// Which likes ListLikes returns by whether they are a like or a dislike.
+type LikesQuery_LikedFilter int32
+
+const (
+	LikesQuery_ALL           LikesQuery_LikedFilter = 0
+	LikesQuery_LIKED_ONLY    LikesQuery_LikedFilter = 1
+	LikesQuery_DISLIKED_ONLY LikesQuery_LikedFilter = 2
+)
+
+var LikesQuery_LikedFilter_name = map[int32]string{
+	0: "ALL",
+	1: "LIKED_ONLY",
+	2: "DISLIKED_ONLY",
+}
+
+var LikesQuery_LikedFilter_value = map[string]int32{
+	"ALL":           0,
+	"LIKED_ONLY":    1,
+	"DISLIKED_ONLY": 2,
+}
+
+func (x LikesQuery_LikedFilter) String() string {
+	return proto.EnumName(LikesQuery_LikedFilter_name, int32(x))
+}
+
+func (LikesQuery_LikedFilter) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{8, 0}
+}
+
+// The order of the likes returned by ListLikes.
+type LikesQuery_OrderBy int32
+
+const (
+	// Grouped by RefType in the order of their ids, and in the order the
+	// likes were first saved to a RefType within it
+	LikesQuery_SAVED           LikesQuery_OrderBy = 0
+	LikesQuery_CREATED_AT_ASC  LikesQuery_OrderBy = 1
+	LikesQuery_CREATED_AT_DESC LikesQuery_OrderBy = 2
+	LikesQuery_ID              LikesQuery_OrderBy = 3
+)
+
+var LikesQuery_OrderBy_name = map[int32]string{
+	0: "SAVED",
+	1: "CREATED_AT_ASC",
+	2: "CREATED_AT_DESC",
+	3: "ID",
+}
+
+var LikesQuery_OrderBy_value = map[string]int32{
+	"SAVED":           0,
+	"CREATED_AT_ASC":  1,
+	"CREATED_AT_DESC": 2,
+	"ID":              3,
+}
+
+func (x LikesQuery_OrderBy) String() string {
+	return proto.EnumName(LikesQuery_OrderBy_name, int32(x))
+}
+
+func (LikesQuery_OrderBy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{8, 1}
+}
+
+type RankQuery_OrderBy int32
+
+const (
+	RankQuery_TOTAL RankQuery_OrderBy = 0
+	RankQuery_SCORE RankQuery_OrderBy = 1
+	RankQuery_RATIO RankQuery_OrderBy = 2
+)
+
+var RankQuery_OrderBy_name = map[int32]string{
+	0: "TOTAL",
+	1: "SCORE",
+	2: "RATIO",
+}
+
+var RankQuery_OrderBy_value = map[string]int32{
+	"TOTAL": 0,
+	"SCORE": 1,
+	"RATIO": 2,
+}
+
+func (x RankQuery_OrderBy) String() string {
+	return proto.EnumName(RankQuery_OrderBy_name, int32(x))
+}
+
+func (RankQuery_OrderBy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{9, 0}
+}
+
+type HistogramQuery_BucketSize int32
+
+const (
+	HistogramQuery_DAY  HistogramQuery_BucketSize = 0
+	HistogramQuery_WEEK HistogramQuery_BucketSize = 1
+)
+
+var HistogramQuery_BucketSize_name = map[int32]string{
+	0: "DAY",
+	1: "WEEK",
+}
+
+var HistogramQuery_BucketSize_value = map[string]int32{
+	"DAY":  0,
+	"WEEK": 1,
+}
+
+func (x HistogramQuery_BucketSize) String() string {
+	return proto.EnumName(HistogramQuery_BucketSize_name, int32(x))
+}
+
+func (HistogramQuery_BucketSize) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{11, 0}
+}
+
+type LikeEvent_Type int32
+
+const (
+	LikeEvent_LIKE   LikeEvent_Type = 0
+	LikeEvent_UNLIKE LikeEvent_Type = 1
+	LikeEvent_UPDATE LikeEvent_Type = 2
+	LikeEvent_PURGE  LikeEvent_Type = 3
+	LikeEvent_PARENT LikeEvent_Type = 4
+)
+
+var LikeEvent_Type_name = map[int32]string{
+	0: "LIKE",
+	1: "UNLIKE",
+	2: "UPDATE",
+	3: "PURGE",
+	4: "PARENT",
+}
+
+var LikeEvent_Type_value = map[string]int32{
+	"LIKE":   0,
+	"UNLIKE": 1,
+	"UPDATE": 2,
+	"PURGE":  3,
+	"PARENT": 4,
+}
+
+func (x LikeEvent_Type) String() string {
+	return proto.EnumName(LikeEvent_Type_name, int32(x))
+}
+
+func (LikeEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{14, 0}
+}
+
+type ExportRequest_Format int32
+
+const (
+	ExportRequest_JSON  ExportRequest_Format = 0
+	ExportRequest_JSONL ExportRequest_Format = 1
+	ExportRequest_CSV   ExportRequest_Format = 2
+)
+
+var ExportRequest_Format_name = map[int32]string{
+	0: "JSON",
+	1: "JSONL",
+	2: "CSV",
+}
+
+var ExportRequest_Format_value = map[string]int32{
+	"JSON":  0,
+	"JSONL": 1,
+	"CSV":   2,
+}
+
+func (x ExportRequest_Format) String() string {
+	return proto.EnumName(ExportRequest_Format_name, int32(x))
+}
+
+func (ExportRequest_Format) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{15, 0}
+}
+
 // RefTypes are pointers to the Beer object for the coresponding like.
 // The Id of the RefType would be the respective Beer ID, Review ID, etc.
 type RefType struct {
@@ -37,7 +248,7 @@ func (m *RefType) Reset()         { *m = RefType{} }
 func (m *RefType) String() string { return proto.CompactTextString(m) }
 func (*RefType) ProtoMessage()    {}
 func (*RefType) Descriptor() ([]byte, []int) {
-	return fileDescriptor_beer_likes_147e708ecc268ceb, []int{0}
+	return fileDescriptor_beer_likes_13313009400b234e, []int{0}
 }
 func (m *RefType) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RefType.Unmarshal(m, b)
@@ -71,11 +282,110 @@ func (m *RefType) GetId() string {
 	return ""
 }
 
+// RefTypeParent relates a RefType to its parent, e.g. a review to its beer or
+// a beer to its brewery.
+type RefTypeParent struct {
+	RefType              *RefType `protobuf:"bytes,1,opt,name=ref_type,json=refType,proto3" json:"ref_type,omitempty"`
+	Parent               *RefType `protobuf:"bytes,2,opt,name=parent,proto3" json:"parent,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RefTypeParent) Reset()         { *m = RefTypeParent{} }
+func (m *RefTypeParent) String() string { return proto.CompactTextString(m) }
+func (*RefTypeParent) ProtoMessage()    {}
+func (*RefTypeParent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{1}
+}
+func (m *RefTypeParent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RefTypeParent.Unmarshal(m, b)
+}
+func (m *RefTypeParent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RefTypeParent.Marshal(b, m, deterministic)
+}
+func (dst *RefTypeParent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RefTypeParent.Merge(dst, src)
+}
+func (m *RefTypeParent) XXX_Size() int {
+	return xxx_messageInfo_RefTypeParent.Size(m)
+}
+func (m *RefTypeParent) XXX_DiscardUnknown() {
+	xxx_messageInfo_RefTypeParent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RefTypeParent proto.InternalMessageInfo
+
+func (m *RefTypeParent) GetRefType() *RefType {
+	if m != nil {
+		return m.RefType
+	}
+	return nil
+}
+
+func (m *RefTypeParent) GetParent() *RefType {
+	if m != nil {
+		return m.Parent
+	}
+	return nil
+}
+
+// RefTypeParentsQuery on the RefTypes with a given name.
+type RefTypeParentsQuery struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RefTypeParentsQuery) Reset()         { *m = RefTypeParentsQuery{} }
+func (m *RefTypeParentsQuery) String() string { return proto.CompactTextString(m) }
+func (*RefTypeParentsQuery) ProtoMessage()    {}
+func (*RefTypeParentsQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{2}
+}
+func (m *RefTypeParentsQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RefTypeParentsQuery.Unmarshal(m, b)
+}
+func (m *RefTypeParentsQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RefTypeParentsQuery.Marshal(b, m, deterministic)
+}
+func (dst *RefTypeParentsQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RefTypeParentsQuery.Merge(dst, src)
+}
+func (m *RefTypeParentsQuery) XXX_Size() int {
+	return xxx_messageInfo_RefTypeParentsQuery.Size(m)
+}
+func (m *RefTypeParentsQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_RefTypeParentsQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RefTypeParentsQuery proto.InternalMessageInfo
+
+func (m *RefTypeParentsQuery) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
 // Like are represented as a positive or negative action for a given RefType.
+// Besides likes and dislikes, a like can be another reaction such as
+// "cheers" or "want_to_try", or a "rating" of 1 to 5 stars.
 type Like struct {
-	RefType              *RefType `protobuf:"bytes,1,opt,name=ref_type,json=refType,proto3" json:"ref_type,omitempty"`
-	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	Liked                bool     `protobuf:"varint,3,opt,name=liked,proto3" json:"liked,omitempty"`
+	RefType   *RefType             `protobuf:"bytes,1,opt,name=ref_type,json=refType,proto3" json:"ref_type,omitempty"`
+	Id        string               `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Liked     bool                 `protobuf:"varint,3,opt,name=liked,proto3" json:"liked,omitempty"`
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Version   uint64               `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	DeletedAt *timestamp.Timestamp `protobuf:"bytes,6,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	UserId    string               `protobuf:"bytes,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Lower case letters, digits and underscores. The server sets "like" or
+	// "dislike" from liked when it is empty, and liked from a "like" or
+	// "dislike" reaction. Other reactions keep the liked they were sent with,
+	// which is not counted as a like or dislike.
+	Reaction             string   `protobuf:"bytes,8,opt,name=reaction,proto3" json:"reaction,omitempty"`
+	Value                int32    `protobuf:"varint,9,opt,name=value,proto3" json:"value,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -85,7 +395,7 @@ func (m *Like) Reset()         { *m = Like{} }
 func (m *Like) String() string { return proto.CompactTextString(m) }
 func (*Like) ProtoMessage()    {}
 func (*Like) Descriptor() ([]byte, []int) {
-	return fileDescriptor_beer_likes_147e708ecc268ceb, []int{1}
+	return fileDescriptor_beer_likes_13313009400b234e, []int{3}
 }
 func (m *Like) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Like.Unmarshal(m, b)
@@ -126,19 +436,68 @@ func (m *Like) GetLiked() bool {
 	return false
 }
 
+func (m *Like) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *Like) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Like) GetDeletedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.DeletedAt
+	}
+	return nil
+}
+
+func (m *Like) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *Like) GetReaction() string {
+	if m != nil {
+		return m.Reaction
+	}
+	return ""
+}
+
+func (m *Like) GetValue() int32 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
 // LikeQuery on for a given RefType.
 type LikeQuery struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// DeleteLike and UndeleteLike fail with ABORTED unless the like is at this
+	// version. 0 skips the check.
+	ExpectedVersion uint64 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	IncludeDeleted  bool   `protobuf:"varint,3,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	// The fields of the returned like, e.g. "id" and "liked", or every field if
+	// it is empty.
+	ReadMask             *field_mask.FieldMask `protobuf:"bytes,4,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *LikeQuery) Reset()         { *m = LikeQuery{} }
 func (m *LikeQuery) String() string { return proto.CompactTextString(m) }
 func (*LikeQuery) ProtoMessage()    {}
 func (*LikeQuery) Descriptor() ([]byte, []int) {
-	return fileDescriptor_beer_likes_147e708ecc268ceb, []int{2}
+	return fileDescriptor_beer_likes_13313009400b234e, []int{4}
 }
 func (m *LikeQuery) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LikeQuery.Unmarshal(m, b)
@@ -165,159 +524,2124 @@ func (m *LikeQuery) GetId() string {
 	return ""
 }
 
-// LikesQuery on for a given RefType.
-type LikesQuery struct {
-	RefType              *RefType `protobuf:"bytes,1,opt,name=ref_type,json=refType,proto3" json:"ref_type,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *LikeQuery) GetExpectedVersion() uint64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
 }
 
-func (m *LikesQuery) Reset()         { *m = LikesQuery{} }
-func (m *LikesQuery) String() string { return proto.CompactTextString(m) }
-func (*LikesQuery) ProtoMessage()    {}
-func (*LikesQuery) Descriptor() ([]byte, []int) {
-	return fileDescriptor_beer_likes_147e708ecc268ceb, []int{3}
+func (m *LikeQuery) GetIncludeDeleted() bool {
+	if m != nil {
+		return m.IncludeDeleted
+	}
+	return false
 }
-func (m *LikesQuery) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_LikesQuery.Unmarshal(m, b)
+
+func (m *LikeQuery) GetReadMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.ReadMask
+	}
+	return nil
 }
-func (m *LikesQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_LikesQuery.Marshal(b, m, deterministic)
+
+// UpdateLikeRequest changes whether a like is a like or a dislike, and its
+// RefType if it is set.
+type UpdateLikeRequest struct {
+	Like *Like `protobuf:"bytes,1,opt,name=like,proto3" json:"like,omitempty"`
+	// The update fails with ABORTED unless the like is at this version. 0 skips
+	// the check.
+	ExpectedVersion uint64 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	// Only these fields of the like are updated: liked, reaction, value and
+	// ref_type. An empty mask updates liked, the reaction and the RefType if it
+	// is set. Updating liked without the reaction makes the like a like or a
+	// dislike, whatever its reaction was.
+	UpdateMask *field_mask.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// The fields of the returned like, or every field if it is empty.
+	ReadMask             *field_mask.FieldMask `protobuf:"bytes,4,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
-func (dst *LikesQuery) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LikesQuery.Merge(dst, src)
+
+func (m *UpdateLikeRequest) Reset()         { *m = UpdateLikeRequest{} }
+func (m *UpdateLikeRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateLikeRequest) ProtoMessage()    {}
+func (*UpdateLikeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{5}
 }
-func (m *LikesQuery) XXX_Size() int {
-	return xxx_messageInfo_LikesQuery.Size(m)
+func (m *UpdateLikeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateLikeRequest.Unmarshal(m, b)
 }
-func (m *LikesQuery) XXX_DiscardUnknown() {
-	xxx_messageInfo_LikesQuery.DiscardUnknown(m)
+func (m *UpdateLikeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateLikeRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdateLikeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateLikeRequest.Merge(dst, src)
+}
+func (m *UpdateLikeRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateLikeRequest.Size(m)
+}
+func (m *UpdateLikeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateLikeRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_LikesQuery proto.InternalMessageInfo
+var xxx_messageInfo_UpdateLikeRequest proto.InternalMessageInfo
 
-func (m *LikesQuery) GetRefType() *RefType {
+func (m *UpdateLikeRequest) GetLike() *Like {
 	if m != nil {
-		return m.RefType
+		return m.Like
 	}
 	return nil
 }
 
-// Collection of likes
-// If a like could not be found, the total count is 0
-type LikesSummary struct {
-	Likes []*Like `protobuf:"bytes,1,rep,name=likes,proto3" json:"likes,omitempty"`
-	Total int32   `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
-	// google.protobuf.Timestamp elapsed_time = 3;
-	ElapsedTime          uint64   `protobuf:"varint,3,opt,name=elapsed_time,json=elapsedTime,proto3" json:"elapsed_time,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *UpdateLikeRequest) GetExpectedVersion() uint64 {
+	if m != nil {
+		return m.ExpectedVersion
+	}
+	return 0
 }
 
-func (m *LikesSummary) Reset()         { *m = LikesSummary{} }
-func (m *LikesSummary) String() string { return proto.CompactTextString(m) }
-func (*LikesSummary) ProtoMessage()    {}
-func (*LikesSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_beer_likes_147e708ecc268ceb, []int{4}
+func (m *UpdateLikeRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
 }
-func (m *LikesSummary) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_LikesSummary.Unmarshal(m, b)
+
+func (m *UpdateLikeRequest) GetReadMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.ReadMask
+	}
+	return nil
 }
-func (m *LikesSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_LikesSummary.Marshal(b, m, deterministic)
+
+// ToggleLikeRequest sets the state of the like of a user for a RefType.
+type ToggleLikeRequest struct {
+	UserId  string                   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RefType *RefType                 `protobuf:"bytes,2,opt,name=ref_type,json=refType,proto3" json:"ref_type,omitempty"`
+	Target  ToggleLikeRequest_Target `protobuf:"varint,3,opt,name=target,proto3,enum=beerlikes.ToggleLikeRequest_Target" json:"target,omitempty"`
+	// The fields of the returned like, or every field if it is empty.
+	ReadMask             *field_mask.FieldMask `protobuf:"bytes,4,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
-func (dst *LikesSummary) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LikesSummary.Merge(dst, src)
+
+func (m *ToggleLikeRequest) Reset()         { *m = ToggleLikeRequest{} }
+func (m *ToggleLikeRequest) String() string { return proto.CompactTextString(m) }
+func (*ToggleLikeRequest) ProtoMessage()    {}
+func (*ToggleLikeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{6}
 }
-func (m *LikesSummary) XXX_Size() int {
-	return xxx_messageInfo_LikesSummary.Size(m)
+func (m *ToggleLikeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ToggleLikeRequest.Unmarshal(m, b)
 }
-func (m *LikesSummary) XXX_DiscardUnknown() {
-	xxx_messageInfo_LikesSummary.DiscardUnknown(m)
+func (m *ToggleLikeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ToggleLikeRequest.Marshal(b, m, deterministic)
+}
+func (dst *ToggleLikeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ToggleLikeRequest.Merge(dst, src)
+}
+func (m *ToggleLikeRequest) XXX_Size() int {
+	return xxx_messageInfo_ToggleLikeRequest.Size(m)
+}
+func (m *ToggleLikeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ToggleLikeRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_LikesSummary proto.InternalMessageInfo
+var xxx_messageInfo_ToggleLikeRequest proto.InternalMessageInfo
 
-func (m *LikesSummary) GetLikes() []*Like {
+func (m *ToggleLikeRequest) GetUserId() string {
 	if m != nil {
-		return m.Likes
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *ToggleLikeRequest) GetRefType() *RefType {
+	if m != nil {
+		return m.RefType
 	}
 	return nil
 }
 
-func (m *LikesSummary) GetTotal() int32 {
+func (m *ToggleLikeRequest) GetTarget() ToggleLikeRequest_Target {
 	if m != nil {
-		return m.Total
+		return m.Target
 	}
-	return 0
+	return ToggleLikeRequest_TOGGLE
 }
 
-func (m *LikesSummary) GetElapsedTime() uint64 {
+func (m *ToggleLikeRequest) GetReadMask() *field_mask.FieldMask {
 	if m != nil {
-		return m.ElapsedTime
+		return m.ReadMask
 	}
-	return 0
+	return nil
 }
 
-func init() {
-	proto.RegisterType((*RefType)(nil), "beerlikes.RefType")
-	proto.RegisterType((*Like)(nil), "beerlikes.Like")
-	proto.RegisterType((*LikeQuery)(nil), "beerlikes.LikeQuery")
-	proto.RegisterType((*LikesQuery)(nil), "beerlikes.LikesQuery")
-	proto.RegisterType((*LikesSummary)(nil), "beerlikes.LikesSummary")
+// ToggleLikeResponse is the like of the user after a ToggleLike, deleted if it
+// was cleared and empty if there was none, and the summary of the RefType
+// without the likes.
+type ToggleLikeResponse struct {
+	Like                 *Like         `protobuf:"bytes,1,opt,name=like,proto3" json:"like,omitempty"`
+	Summary              *LikesSummary `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
+func (m *ToggleLikeResponse) Reset()         { *m = ToggleLikeResponse{} }
+func (m *ToggleLikeResponse) String() string { return proto.CompactTextString(m) }
+func (*ToggleLikeResponse) ProtoMessage()    {}
+func (*ToggleLikeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{7}
+}
+func (m *ToggleLikeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ToggleLikeResponse.Unmarshal(m, b)
+}
+func (m *ToggleLikeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ToggleLikeResponse.Marshal(b, m, deterministic)
+}
+func (dst *ToggleLikeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ToggleLikeResponse.Merge(dst, src)
+}
+func (m *ToggleLikeResponse) XXX_Size() int {
+	return xxx_messageInfo_ToggleLikeResponse.Size(m)
+}
+func (m *ToggleLikeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ToggleLikeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ToggleLikeResponse proto.InternalMessageInfo
+
+func (m *ToggleLikeResponse) GetLike() *Like {
+	if m != nil {
+		return m.Like
+	}
+	return nil
+}
+
+func (m *ToggleLikeResponse) GetSummary() *LikesSummary {
+	if m != nil {
+		return m.Summary
+	}
+	return nil
+}
+
+// LikesQuery on for a given RefType.
+type LikesQuery struct {
+	RefType *RefType `protobuf:"bytes,1,opt,name=ref_type,json=refType,proto3" json:"ref_type,omitempty"`
+	// GetLikesSummary only returns a not_modified summary if the version of the
+	// likes is still this one.
+	IfVersionChanged uint64 `protobuf:"varint,2,opt,name=if_version_changed,json=ifVersionChanged,proto3" json:"if_version_changed,omitempty"`
+	IncludeDeleted   bool   `protobuf:"varint,3,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	Rollup           bool   `protobuf:"varint,4,opt,name=rollup,proto3" json:"rollup,omitempty"`
+	// ListLikes and WatchLikes match the RefTypes with the ref_type name and an
+	// id with this prefix. The ref_type id must be empty. GetLikesSummary
+	// rejects it.
+	IdPrefix string `protobuf:"bytes,5,opt,name=id_prefix,json=idPrefix,proto3" json:"id_prefix,omitempty"`
+	// ListLikes and WatchLikes match the RefTypes with the ref_type name and
+	// one of these ids. The ref_type id must be empty. GetLikesSummary rejects
+	// them.
+	Ids []string `protobuf:"bytes,6,rep,name=ids,proto3" json:"ids,omitempty"`
+	// The following filters, order and limit only apply to ListLikes, and
+	// GetLikesSummary rejects them.
+	Liked         LikesQuery_LikedFilter `protobuf:"varint,7,opt,name=liked,proto3,enum=beerlikes.LikesQuery_LikedFilter" json:"liked,omitempty"`
+	UserId        string                 `protobuf:"bytes,8,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CreatedAfter  *timestamp.Timestamp   `protobuf:"bytes,9,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore *timestamp.Timestamp   `protobuf:"bytes,10,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	LikeIds       []string               `protobuf:"bytes,11,rep,name=like_ids,json=likeIds,proto3" json:"like_ids,omitempty"`
+	OrderBy       LikesQuery_OrderBy     `protobuf:"varint,12,opt,name=order_by,json=orderBy,proto3,enum=beerlikes.LikesQuery_OrderBy" json:"order_by,omitempty"`
+	Limit         int32                  `protobuf:"varint,13,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The fields of the likes returned by ListLikes, GetLikesSummary and
+	// WatchLikes, or every field if it is empty.
+	ReadMask             *field_mask.FieldMask `protobuf:"bytes,14,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *LikesQuery) Reset()         { *m = LikesQuery{} }
+func (m *LikesQuery) String() string { return proto.CompactTextString(m) }
+func (*LikesQuery) ProtoMessage()    {}
+func (*LikesQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{8}
+}
+func (m *LikesQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LikesQuery.Unmarshal(m, b)
+}
+func (m *LikesQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LikesQuery.Marshal(b, m, deterministic)
+}
+func (dst *LikesQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LikesQuery.Merge(dst, src)
+}
+func (m *LikesQuery) XXX_Size() int {
+	return xxx_messageInfo_LikesQuery.Size(m)
+}
+func (m *LikesQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_LikesQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LikesQuery proto.InternalMessageInfo
+
+func (m *LikesQuery) GetRefType() *RefType {
+	if m != nil {
+		return m.RefType
+	}
+	return nil
+}
+
+func (m *LikesQuery) GetIfVersionChanged() uint64 {
+	if m != nil {
+		return m.IfVersionChanged
+	}
+	return 0
+}
+
+func (m *LikesQuery) GetIncludeDeleted() bool {
+	if m != nil {
+		return m.IncludeDeleted
+	}
+	return false
+}
+
+func (m *LikesQuery) GetRollup() bool {
+	if m != nil {
+		return m.Rollup
+	}
+	return false
+}
+
+func (m *LikesQuery) GetIdPrefix() string {
+	if m != nil {
+		return m.IdPrefix
+	}
+	return ""
+}
+
+func (m *LikesQuery) GetIds() []string {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+func (m *LikesQuery) GetLiked() LikesQuery_LikedFilter {
+	if m != nil {
+		return m.Liked
+	}
+	return LikesQuery_ALL
+}
+
+func (m *LikesQuery) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *LikesQuery) GetCreatedAfter() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAfter
+	}
+	return nil
+}
+
+func (m *LikesQuery) GetCreatedBefore() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedBefore
+	}
+	return nil
+}
+
+func (m *LikesQuery) GetLikeIds() []string {
+	if m != nil {
+		return m.LikeIds
+	}
+	return nil
+}
+
+func (m *LikesQuery) GetOrderBy() LikesQuery_OrderBy {
+	if m != nil {
+		return m.OrderBy
+	}
+	return LikesQuery_SAVED
+}
+
+func (m *LikesQuery) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *LikesQuery) GetReadMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.ReadMask
+	}
+	return nil
+}
+
+// RankQuery on for all the RefTypes with a given name.
+type RankQuery struct {
+	Name                 string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	OrderBy              RankQuery_OrderBy `protobuf:"varint,2,opt,name=order_by,json=orderBy,proto3,enum=beerlikes.RankQuery_OrderBy" json:"order_by,omitempty"`
+	Limit                int32             `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Rollup               bool              `protobuf:"varint,4,opt,name=rollup,proto3" json:"rollup,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *RankQuery) Reset()         { *m = RankQuery{} }
+func (m *RankQuery) String() string { return proto.CompactTextString(m) }
+func (*RankQuery) ProtoMessage()    {}
+func (*RankQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{9}
+}
+func (m *RankQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RankQuery.Unmarshal(m, b)
+}
+func (m *RankQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RankQuery.Marshal(b, m, deterministic)
+}
+func (dst *RankQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RankQuery.Merge(dst, src)
+}
+func (m *RankQuery) XXX_Size() int {
+	return xxx_messageInfo_RankQuery.Size(m)
+}
+func (m *RankQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_RankQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RankQuery proto.InternalMessageInfo
+
+func (m *RankQuery) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RankQuery) GetOrderBy() RankQuery_OrderBy {
+	if m != nil {
+		return m.OrderBy
+	}
+	return RankQuery_TOTAL
+}
+
+func (m *RankQuery) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *RankQuery) GetRollup() bool {
+	if m != nil {
+		return m.Rollup
+	}
+	return false
+}
+
+// Collection of likes
+// If a like could not be found, the total count is 0
+// The like, dislike and total counts and the scores only include the "like"
+// and "dislike" reactions.
+type LikesSummary struct {
+	Likes []*Like `protobuf:"bytes,1,rep,name=likes,proto3" json:"likes,omitempty"`
+	Total int32   `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	// google.protobuf.Timestamp elapsed_time = 3;
+	ElapsedTime          uint64           `protobuf:"varint,3,opt,name=elapsed_time,json=elapsedTime,proto3" json:"elapsed_time,omitempty"`
+	RefType              *RefType         `protobuf:"bytes,4,opt,name=ref_type,json=refType,proto3" json:"ref_type,omitempty"`
+	LikeCount            int32            `protobuf:"varint,5,opt,name=like_count,json=likeCount,proto3" json:"like_count,omitempty"`
+	DislikeCount         int32            `protobuf:"varint,6,opt,name=dislike_count,json=dislikeCount,proto3" json:"dislike_count,omitempty"`
+	LikeRatio            float64          `protobuf:"fixed64,7,opt,name=like_ratio,json=likeRatio,proto3" json:"like_ratio,omitempty"`
+	Score                float64          `protobuf:"fixed64,8,opt,name=score,proto3" json:"score,omitempty"`
+	Version              uint64           `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	NotModified          bool             `protobuf:"varint,10,opt,name=not_modified,json=notModified,proto3" json:"not_modified,omitempty"`
+	DeletedCount         int32            `protobuf:"varint,11,opt,name=deleted_count,json=deletedCount,proto3" json:"deleted_count,omitempty"`
+	ReactionCounts       map[string]int32 `protobuf:"bytes,12,rep,name=reaction_counts,json=reactionCounts,proto3" json:"reaction_counts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	RatingCount          int32            `protobuf:"varint,13,opt,name=rating_count,json=ratingCount,proto3" json:"rating_count,omitempty"`
+	AverageRating        float64          `protobuf:"fixed64,14,opt,name=average_rating,json=averageRating,proto3" json:"average_rating,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *LikesSummary) Reset()         { *m = LikesSummary{} }
+func (m *LikesSummary) String() string { return proto.CompactTextString(m) }
+func (*LikesSummary) ProtoMessage()    {}
+func (*LikesSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{10}
+}
+func (m *LikesSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LikesSummary.Unmarshal(m, b)
+}
+func (m *LikesSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LikesSummary.Marshal(b, m, deterministic)
+}
+func (dst *LikesSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LikesSummary.Merge(dst, src)
+}
+func (m *LikesSummary) XXX_Size() int {
+	return xxx_messageInfo_LikesSummary.Size(m)
+}
+func (m *LikesSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_LikesSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LikesSummary proto.InternalMessageInfo
+
+func (m *LikesSummary) GetLikes() []*Like {
+	if m != nil {
+		return m.Likes
+	}
+	return nil
+}
+
+func (m *LikesSummary) GetTotal() int32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *LikesSummary) GetElapsedTime() uint64 {
+	if m != nil {
+		return m.ElapsedTime
+	}
+	return 0
+}
+
+func (m *LikesSummary) GetRefType() *RefType {
+	if m != nil {
+		return m.RefType
+	}
+	return nil
+}
+
+func (m *LikesSummary) GetLikeCount() int32 {
+	if m != nil {
+		return m.LikeCount
+	}
+	return 0
+}
+
+func (m *LikesSummary) GetDislikeCount() int32 {
+	if m != nil {
+		return m.DislikeCount
+	}
+	return 0
+}
+
+func (m *LikesSummary) GetLikeRatio() float64 {
+	if m != nil {
+		return m.LikeRatio
+	}
+	return 0
+}
+
+func (m *LikesSummary) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *LikesSummary) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *LikesSummary) GetNotModified() bool {
+	if m != nil {
+		return m.NotModified
+	}
+	return false
+}
+
+func (m *LikesSummary) GetDeletedCount() int32 {
+	if m != nil {
+		return m.DeletedCount
+	}
+	return 0
+}
+
+func (m *LikesSummary) GetReactionCounts() map[string]int32 {
+	if m != nil {
+		return m.ReactionCounts
+	}
+	return nil
+}
+
+func (m *LikesSummary) GetRatingCount() int32 {
+	if m != nil {
+		return m.RatingCount
+	}
+	return 0
+}
+
+func (m *LikesSummary) GetAverageRating() float64 {
+	if m != nil {
+		return m.AverageRating
+	}
+	return 0
+}
+
+// HistogramQuery on for a given RefType and time range.
+type HistogramQuery struct {
+	RefType              *RefType                  `protobuf:"bytes,1,opt,name=ref_type,json=refType,proto3" json:"ref_type,omitempty"`
+	StartTime            *timestamp.Timestamp      `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime              *timestamp.Timestamp      `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	BucketSize           HistogramQuery_BucketSize `protobuf:"varint,4,opt,name=bucket_size,json=bucketSize,proto3,enum=beerlikes.HistogramQuery_BucketSize" json:"bucket_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *HistogramQuery) Reset()         { *m = HistogramQuery{} }
+func (m *HistogramQuery) String() string { return proto.CompactTextString(m) }
+func (*HistogramQuery) ProtoMessage()    {}
+func (*HistogramQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{11}
+}
+func (m *HistogramQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistogramQuery.Unmarshal(m, b)
+}
+func (m *HistogramQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistogramQuery.Marshal(b, m, deterministic)
+}
+func (dst *HistogramQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistogramQuery.Merge(dst, src)
+}
+func (m *HistogramQuery) XXX_Size() int {
+	return xxx_messageInfo_HistogramQuery.Size(m)
+}
+func (m *HistogramQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistogramQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistogramQuery proto.InternalMessageInfo
+
+func (m *HistogramQuery) GetRefType() *RefType {
+	if m != nil {
+		return m.RefType
+	}
+	return nil
+}
+
+func (m *HistogramQuery) GetStartTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *HistogramQuery) GetEndTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
+
+func (m *HistogramQuery) GetBucketSize() HistogramQuery_BucketSize {
+	if m != nil {
+		return m.BucketSize
+	}
+	return HistogramQuery_DAY
+}
+
+// HistogramBucket counts the likes created within a time bucket.
+type HistogramBucket struct {
+	StartTime            *timestamp.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	LikeCount            int32                `protobuf:"varint,2,opt,name=like_count,json=likeCount,proto3" json:"like_count,omitempty"`
+	DislikeCount         int32                `protobuf:"varint,3,opt,name=dislike_count,json=dislikeCount,proto3" json:"dislike_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *HistogramBucket) Reset()         { *m = HistogramBucket{} }
+func (m *HistogramBucket) String() string { return proto.CompactTextString(m) }
+func (*HistogramBucket) ProtoMessage()    {}
+func (*HistogramBucket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{12}
+}
+func (m *HistogramBucket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistogramBucket.Unmarshal(m, b)
+}
+func (m *HistogramBucket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistogramBucket.Marshal(b, m, deterministic)
+}
+func (dst *HistogramBucket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistogramBucket.Merge(dst, src)
+}
+func (m *HistogramBucket) XXX_Size() int {
+	return xxx_messageInfo_HistogramBucket.Size(m)
+}
+func (m *HistogramBucket) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistogramBucket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistogramBucket proto.InternalMessageInfo
+
+func (m *HistogramBucket) GetStartTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *HistogramBucket) GetLikeCount() int32 {
+	if m != nil {
+		return m.LikeCount
+	}
+	return 0
+}
+
+func (m *HistogramBucket) GetDislikeCount() int32 {
+	if m != nil {
+		return m.DislikeCount
+	}
+	return 0
+}
+
+// Collection of time buckets for a given RefType, in ascending order.
+// Buckets without any likes are included with zero counts.
+type LikesHistogram struct {
+	RefType              *RefType                  `protobuf:"bytes,1,opt,name=ref_type,json=refType,proto3" json:"ref_type,omitempty"`
+	BucketSize           HistogramQuery_BucketSize `protobuf:"varint,2,opt,name=bucket_size,json=bucketSize,proto3,enum=beerlikes.HistogramQuery_BucketSize" json:"bucket_size,omitempty"`
+	Buckets              []*HistogramBucket        `protobuf:"bytes,3,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	ElapsedTime          uint64                    `protobuf:"varint,4,opt,name=elapsed_time,json=elapsedTime,proto3" json:"elapsed_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *LikesHistogram) Reset()         { *m = LikesHistogram{} }
+func (m *LikesHistogram) String() string { return proto.CompactTextString(m) }
+func (*LikesHistogram) ProtoMessage()    {}
+func (*LikesHistogram) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{13}
+}
+func (m *LikesHistogram) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LikesHistogram.Unmarshal(m, b)
+}
+func (m *LikesHistogram) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LikesHistogram.Marshal(b, m, deterministic)
+}
+func (dst *LikesHistogram) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LikesHistogram.Merge(dst, src)
+}
+func (m *LikesHistogram) XXX_Size() int {
+	return xxx_messageInfo_LikesHistogram.Size(m)
+}
+func (m *LikesHistogram) XXX_DiscardUnknown() {
+	xxx_messageInfo_LikesHistogram.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LikesHistogram proto.InternalMessageInfo
+
+func (m *LikesHistogram) GetRefType() *RefType {
+	if m != nil {
+		return m.RefType
+	}
+	return nil
+}
+
+func (m *LikesHistogram) GetBucketSize() HistogramQuery_BucketSize {
+	if m != nil {
+		return m.BucketSize
+	}
+	return HistogramQuery_DAY
+}
+
+func (m *LikesHistogram) GetBuckets() []*HistogramBucket {
+	if m != nil {
+		return m.Buckets
+	}
+	return nil
+}
+
+func (m *LikesHistogram) GetElapsedTime() uint64 {
+	if m != nil {
+		return m.ElapsedTime
+	}
+	return 0
+}
+
+// LikeEvent records a single change to the likes. The server appends every
+// event to its event log and rebuilds its state by replaying them.
+type LikeEvent struct {
+	Type     LikeEvent_Type       `protobuf:"varint,1,opt,name=type,proto3,enum=beerlikes.LikeEvent_Type" json:"type,omitempty"`
+	Sequence uint64               `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Time     *timestamp.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	Like     *Like                `protobuf:"bytes,4,opt,name=like,proto3" json:"like,omitempty"`
+	Parent   *RefTypeParent       `protobuf:"bytes,5,opt,name=parent,proto3" json:"parent,omitempty"`
+	// Only set for UPDATE events, and LIKE events of an import that replaced a
+	// like, that moved the like to another RefType
+	PreviousRefType      *RefType `protobuf:"bytes,6,opt,name=previous_ref_type,json=previousRefType,proto3" json:"previous_ref_type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LikeEvent) Reset()         { *m = LikeEvent{} }
+func (m *LikeEvent) String() string { return proto.CompactTextString(m) }
+func (*LikeEvent) ProtoMessage()    {}
+func (*LikeEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{14}
+}
+func (m *LikeEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LikeEvent.Unmarshal(m, b)
+}
+func (m *LikeEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LikeEvent.Marshal(b, m, deterministic)
+}
+func (dst *LikeEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LikeEvent.Merge(dst, src)
+}
+func (m *LikeEvent) XXX_Size() int {
+	return xxx_messageInfo_LikeEvent.Size(m)
+}
+func (m *LikeEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_LikeEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LikeEvent proto.InternalMessageInfo
+
+func (m *LikeEvent) GetType() LikeEvent_Type {
+	if m != nil {
+		return m.Type
+	}
+	return LikeEvent_LIKE
+}
+
+func (m *LikeEvent) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *LikeEvent) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *LikeEvent) GetLike() *Like {
+	if m != nil {
+		return m.Like
+	}
+	return nil
+}
+
+func (m *LikeEvent) GetParent() *RefTypeParent {
+	if m != nil {
+		return m.Parent
+	}
+	return nil
+}
+
+func (m *LikeEvent) GetPreviousRefType() *RefType {
+	if m != nil {
+		return m.PreviousRefType
+	}
+	return nil
+}
+
+// ExportRequest selects the encoding of an export of all the likes.
+type ExportRequest struct {
+	Format               ExportRequest_Format `protobuf:"varint,1,opt,name=format,proto3,enum=beerlikes.ExportRequest_Format" json:"format,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ExportRequest) Reset()         { *m = ExportRequest{} }
+func (m *ExportRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportRequest) ProtoMessage()    {}
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{15}
+}
+func (m *ExportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportRequest.Unmarshal(m, b)
+}
+func (m *ExportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportRequest.Marshal(b, m, deterministic)
+}
+func (dst *ExportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportRequest.Merge(dst, src)
+}
+func (m *ExportRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportRequest.Size(m)
+}
+func (m *ExportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportRequest proto.InternalMessageInfo
+
+func (m *ExportRequest) GetFormat() ExportRequest_Format {
+	if m != nil {
+		return m.Format
+	}
+	return ExportRequest_JSON
+}
+
+// ExportChunk is the next part of an export.
+type ExportChunk struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportChunk) Reset()         { *m = ExportChunk{} }
+func (m *ExportChunk) String() string { return proto.CompactTextString(m) }
+func (*ExportChunk) ProtoMessage()    {}
+func (*ExportChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{16}
+}
+func (m *ExportChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportChunk.Unmarshal(m, b)
+}
+func (m *ExportChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportChunk.Marshal(b, m, deterministic)
+}
+func (dst *ExportChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportChunk.Merge(dst, src)
+}
+func (m *ExportChunk) XXX_Size() int {
+	return xxx_messageInfo_ExportChunk.Size(m)
+}
+func (m *ExportChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportChunk proto.InternalMessageInfo
+
+func (m *ExportChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// ImportSummary is the result of an import.
+type ImportSummary struct {
+	Imported             int32          `protobuf:"varint,1,opt,name=imported,proto3" json:"imported,omitempty"`
+	Rejected             int32          `protobuf:"varint,2,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	Errors               []*ImportError `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	ElapsedTime          uint64         `protobuf:"varint,4,opt,name=elapsed_time,json=elapsedTime,proto3" json:"elapsed_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ImportSummary) Reset()         { *m = ImportSummary{} }
+func (m *ImportSummary) String() string { return proto.CompactTextString(m) }
+func (*ImportSummary) ProtoMessage()    {}
+func (*ImportSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{17}
+}
+func (m *ImportSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportSummary.Unmarshal(m, b)
+}
+func (m *ImportSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportSummary.Marshal(b, m, deterministic)
+}
+func (dst *ImportSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportSummary.Merge(dst, src)
+}
+func (m *ImportSummary) XXX_Size() int {
+	return xxx_messageInfo_ImportSummary.Size(m)
+}
+func (m *ImportSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportSummary proto.InternalMessageInfo
+
+func (m *ImportSummary) GetImported() int32 {
+	if m != nil {
+		return m.Imported
+	}
+	return 0
+}
+
+func (m *ImportSummary) GetRejected() int32 {
+	if m != nil {
+		return m.Rejected
+	}
+	return 0
+}
+
+func (m *ImportSummary) GetErrors() []*ImportError {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+func (m *ImportSummary) GetElapsedTime() uint64 {
+	if m != nil {
+		return m.ElapsedTime
+	}
+	return 0
+}
+
+// ImportError explains why a like was rejected.
+type ImportError struct {
+	Index                int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportError) Reset()         { *m = ImportError{} }
+func (m *ImportError) String() string { return proto.CompactTextString(m) }
+func (*ImportError) ProtoMessage()    {}
+func (*ImportError) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{18}
+}
+func (m *ImportError) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportError.Unmarshal(m, b)
+}
+func (m *ImportError) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportError.Marshal(b, m, deterministic)
+}
+func (dst *ImportError) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportError.Merge(dst, src)
+}
+func (m *ImportError) XXX_Size() int {
+	return xxx_messageInfo_ImportError.Size(m)
+}
+func (m *ImportError) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportError.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportError proto.InternalMessageInfo
+
+func (m *ImportError) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *ImportError) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+// TenantsQuery selects the tenants of ListTenants.
+type TenantsQuery struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TenantsQuery) Reset()         { *m = TenantsQuery{} }
+func (m *TenantsQuery) String() string { return proto.CompactTextString(m) }
+func (*TenantsQuery) ProtoMessage()    {}
+func (*TenantsQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{19}
+}
+func (m *TenantsQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TenantsQuery.Unmarshal(m, b)
+}
+func (m *TenantsQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TenantsQuery.Marshal(b, m, deterministic)
+}
+func (dst *TenantsQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantsQuery.Merge(dst, src)
+}
+func (m *TenantsQuery) XXX_Size() int {
+	return xxx_messageInfo_TenantsQuery.Size(m)
+}
+func (m *TenantsQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantsQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantsQuery proto.InternalMessageInfo
+
+func (m *TenantsQuery) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// TenantStats are the counts of likes and requests of a tenant.
+type TenantStats struct {
+	Tenant               string   `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Likes                int32    `protobuf:"varint,2,opt,name=likes,proto3" json:"likes,omitempty"`
+	DeletedLikes         int32    `protobuf:"varint,3,opt,name=deleted_likes,json=deletedLikes,proto3" json:"deleted_likes,omitempty"`
+	RefTypes             int32    `protobuf:"varint,4,opt,name=ref_types,json=refTypes,proto3" json:"ref_types,omitempty"`
+	Requests             uint64   `protobuf:"varint,5,opt,name=requests,proto3" json:"requests,omitempty"`
+	Errors               uint64   `protobuf:"varint,6,opt,name=errors,proto3" json:"errors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TenantStats) Reset()         { *m = TenantStats{} }
+func (m *TenantStats) String() string { return proto.CompactTextString(m) }
+func (*TenantStats) ProtoMessage()    {}
+func (*TenantStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{20}
+}
+func (m *TenantStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TenantStats.Unmarshal(m, b)
+}
+func (m *TenantStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TenantStats.Marshal(b, m, deterministic)
+}
+func (dst *TenantStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantStats.Merge(dst, src)
+}
+func (m *TenantStats) XXX_Size() int {
+	return xxx_messageInfo_TenantStats.Size(m)
+}
+func (m *TenantStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantStats proto.InternalMessageInfo
+
+func (m *TenantStats) GetTenant() string {
+	if m != nil {
+		return m.Tenant
+	}
+	return ""
+}
+
+func (m *TenantStats) GetLikes() int32 {
+	if m != nil {
+		return m.Likes
+	}
+	return 0
+}
+
+func (m *TenantStats) GetDeletedLikes() int32 {
+	if m != nil {
+		return m.DeletedLikes
+	}
+	return 0
+}
+
+func (m *TenantStats) GetRefTypes() int32 {
+	if m != nil {
+		return m.RefTypes
+	}
+	return 0
+}
+
+func (m *TenantStats) GetRequests() uint64 {
+	if m != nil {
+		return m.Requests
+	}
+	return 0
+}
+
+func (m *TenantStats) GetErrors() uint64 {
+	if m != nil {
+		return m.Errors
+	}
+	return 0
+}
+
+// LoggingConfig is the log level and format of the server.
+type LoggingConfig struct {
+	Level                string   `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	Format               string   `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LoggingConfig) Reset()         { *m = LoggingConfig{} }
+func (m *LoggingConfig) String() string { return proto.CompactTextString(m) }
+func (*LoggingConfig) ProtoMessage()    {}
+func (*LoggingConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{21}
+}
+func (m *LoggingConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoggingConfig.Unmarshal(m, b)
+}
+func (m *LoggingConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoggingConfig.Marshal(b, m, deterministic)
+}
+func (dst *LoggingConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoggingConfig.Merge(dst, src)
+}
+func (m *LoggingConfig) XXX_Size() int {
+	return xxx_messageInfo_LoggingConfig.Size(m)
+}
+func (m *LoggingConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoggingConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LoggingConfig proto.InternalMessageInfo
+
+func (m *LoggingConfig) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *LoggingConfig) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+// AuditQuery selects the audit records of WatchAudit.
+type AuditQuery struct {
+	Method               string   `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditQuery) Reset()         { *m = AuditQuery{} }
+func (m *AuditQuery) String() string { return proto.CompactTextString(m) }
+func (*AuditQuery) ProtoMessage()    {}
+func (*AuditQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{22}
+}
+func (m *AuditQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditQuery.Unmarshal(m, b)
+}
+func (m *AuditQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditQuery.Marshal(b, m, deterministic)
+}
+func (dst *AuditQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditQuery.Merge(dst, src)
+}
+func (m *AuditQuery) XXX_Size() int {
+	return xxx_messageInfo_AuditQuery.Size(m)
+}
+func (m *AuditQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditQuery proto.InternalMessageInfo
+
+func (m *AuditQuery) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+// AuditChange is a like or RefType parent before and after a write. Before is
+// empty for a new like and after is empty for a purged like.
+type AuditChange struct {
+	Before               *Like          `protobuf:"bytes,1,opt,name=before,proto3" json:"before,omitempty"`
+	After                *Like          `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	ParentBefore         *RefTypeParent `protobuf:"bytes,3,opt,name=parent_before,json=parentBefore,proto3" json:"parent_before,omitempty"`
+	ParentAfter          *RefTypeParent `protobuf:"bytes,4,opt,name=parent_after,json=parentAfter,proto3" json:"parent_after,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *AuditChange) Reset()         { *m = AuditChange{} }
+func (m *AuditChange) String() string { return proto.CompactTextString(m) }
+func (*AuditChange) ProtoMessage()    {}
+func (*AuditChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{23}
+}
+func (m *AuditChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditChange.Unmarshal(m, b)
+}
+func (m *AuditChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditChange.Marshal(b, m, deterministic)
+}
+func (dst *AuditChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditChange.Merge(dst, src)
+}
+func (m *AuditChange) XXX_Size() int {
+	return xxx_messageInfo_AuditChange.Size(m)
+}
+func (m *AuditChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditChange proto.InternalMessageInfo
+
+func (m *AuditChange) GetBefore() *Like {
+	if m != nil {
+		return m.Before
+	}
+	return nil
+}
+
+func (m *AuditChange) GetAfter() *Like {
+	if m != nil {
+		return m.After
+	}
+	return nil
+}
+
+func (m *AuditChange) GetParentBefore() *RefTypeParent {
+	if m != nil {
+		return m.ParentBefore
+	}
+	return nil
+}
+
+func (m *AuditChange) GetParentAfter() *RefTypeParent {
+	if m != nil {
+		return m.ParentAfter
+	}
+	return nil
+}
+
+// AuditRecord records who made a write, when and what it changed.
+type AuditRecord struct {
+	Sequence  uint64               `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Time      *timestamp.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Principal string               `protobuf:"bytes,3,opt,name=principal,proto3" json:"principal,omitempty"`
+	Tenant    string               `protobuf:"bytes,4,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Method    string               `protobuf:"bytes,5,opt,name=method,proto3" json:"method,omitempty"`
+	Peer      string               `protobuf:"bytes,6,opt,name=peer,proto3" json:"peer,omitempty"`
+	RequestId string               `protobuf:"bytes,7,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Code      string               `protobuf:"bytes,8,opt,name=code,proto3" json:"code,omitempty"`
+	Error     string               `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	Changes   []*AuditChange       `protobuf:"bytes,10,rep,name=changes,proto3" json:"changes,omitempty"`
+	// The write repeated the idempotency key of an earlier write, whose response
+	// was returned without changing anything.
+	Replayed bool `protobuf:"varint,13,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	// The hash chain of the file audit sink: the hash of the previous record,
+	// and the hex SHA-256 of the deterministic protobuf encoding of this record
+	// without its hash.
+	PreviousHash         string   `protobuf:"bytes,11,opt,name=previous_hash,json=previousHash,proto3" json:"previous_hash,omitempty"`
+	Hash                 string   `protobuf:"bytes,12,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditRecord) Reset()         { *m = AuditRecord{} }
+func (m *AuditRecord) String() string { return proto.CompactTextString(m) }
+func (*AuditRecord) ProtoMessage()    {}
+func (*AuditRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_beer_likes_13313009400b234e, []int{24}
+}
+func (m *AuditRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditRecord.Unmarshal(m, b)
+}
+func (m *AuditRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditRecord.Marshal(b, m, deterministic)
+}
+func (dst *AuditRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditRecord.Merge(dst, src)
+}
+func (m *AuditRecord) XXX_Size() int {
+	return xxx_messageInfo_AuditRecord.Size(m)
+}
+func (m *AuditRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditRecord proto.InternalMessageInfo
+
+func (m *AuditRecord) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *AuditRecord) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *AuditRecord) GetPrincipal() string {
+	if m != nil {
+		return m.Principal
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetTenant() string {
+	if m != nil {
+		return m.Tenant
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetChanges() []*AuditChange {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+func (m *AuditRecord) GetReplayed() bool {
+	if m != nil {
+		return m.Replayed
+	}
+	return false
+}
+
+func (m *AuditRecord) GetPreviousHash() string {
+	if m != nil {
+		return m.PreviousHash
+	}
+	return ""
+}
+
+func (m *AuditRecord) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("beerlikes.ToggleLikeRequest_Target", ToggleLikeRequest_Target_name, ToggleLikeRequest_Target_value)
+	proto.RegisterEnum("beerlikes.LikesQuery_LikedFilter", LikesQuery_LikedFilter_name, LikesQuery_LikedFilter_value)
+	proto.RegisterEnum("beerlikes.LikesQuery_OrderBy", LikesQuery_OrderBy_name, LikesQuery_OrderBy_value)
+	proto.RegisterEnum("beerlikes.RankQuery_OrderBy", RankQuery_OrderBy_name, RankQuery_OrderBy_value)
+	proto.RegisterEnum("beerlikes.HistogramQuery_BucketSize", HistogramQuery_BucketSize_name, HistogramQuery_BucketSize_value)
+	proto.RegisterEnum("beerlikes.LikeEvent_Type", LikeEvent_Type_name, LikeEvent_Type_value)
+	proto.RegisterEnum("beerlikes.ExportRequest_Format", ExportRequest_Format_name, ExportRequest_Format_value)
+	proto.RegisterType((*RefType)(nil), "beerlikes.RefType")
+	proto.RegisterType((*RefTypeParent)(nil), "beerlikes.RefTypeParent")
+	proto.RegisterType((*RefTypeParentsQuery)(nil), "beerlikes.RefTypeParentsQuery")
+	proto.RegisterType((*Like)(nil), "beerlikes.Like")
+	proto.RegisterType((*LikeQuery)(nil), "beerlikes.LikeQuery")
+	proto.RegisterType((*UpdateLikeRequest)(nil), "beerlikes.UpdateLikeRequest")
+	proto.RegisterType((*ToggleLikeRequest)(nil), "beerlikes.ToggleLikeRequest")
+	proto.RegisterType((*ToggleLikeResponse)(nil), "beerlikes.ToggleLikeResponse")
+	proto.RegisterType((*LikesQuery)(nil), "beerlikes.LikesQuery")
+	proto.RegisterType((*RankQuery)(nil), "beerlikes.RankQuery")
+	proto.RegisterType((*LikesSummary)(nil), "beerlikes.LikesSummary")
+	proto.RegisterMapType((map[string]int32)(nil), "beerlikes.LikesSummary.ReactionCountsEntry")
+	proto.RegisterType((*HistogramQuery)(nil), "beerlikes.HistogramQuery")
+	proto.RegisterType((*HistogramBucket)(nil), "beerlikes.HistogramBucket")
+	proto.RegisterType((*LikesHistogram)(nil), "beerlikes.LikesHistogram")
+	proto.RegisterType((*LikeEvent)(nil), "beerlikes.LikeEvent")
+	proto.RegisterType((*ExportRequest)(nil), "beerlikes.ExportRequest")
+	proto.RegisterType((*ExportChunk)(nil), "beerlikes.ExportChunk")
+	proto.RegisterType((*ImportSummary)(nil), "beerlikes.ImportSummary")
+	proto.RegisterType((*ImportError)(nil), "beerlikes.ImportError")
+	proto.RegisterType((*TenantsQuery)(nil), "beerlikes.TenantsQuery")
+	proto.RegisterType((*TenantStats)(nil), "beerlikes.TenantStats")
+	proto.RegisterType((*LoggingConfig)(nil), "beerlikes.LoggingConfig")
+	proto.RegisterType((*AuditQuery)(nil), "beerlikes.AuditQuery")
+	proto.RegisterType((*AuditChange)(nil), "beerlikes.AuditChange")
+	proto.RegisterType((*AuditRecord)(nil), "beerlikes.AuditRecord")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
 var _ context.Context
 var _ grpc.ClientConn
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// BeerLikesClient is the client API for BeerLikes service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BeerLikesClient interface {
+	// A simple RPC.
+	//
+	// Obtains the like at a given RefType.
+	//
+	// A like with an empty id is returned if there's no like at the given
+	// reftype. Deleted likes are only returned with include_deleted.
+	GetLike(ctx context.Context, in *LikeQuery, opts ...grpc.CallOption) (*Like, error)
+	// Stream all the Likes at a given RefType
+	// position. Without a ref_type id, stream the likes of every RefType with
+	// the name, or of the ids with the id_prefix, or of the ids.
+	ListLikes(ctx context.Context, in *LikesQuery, opts ...grpc.CallOption) (BeerLikes_ListLikesClient, error)
+	// Batch fetch all the Likes and let the server do the calculations
+	GetLikesSummary(ctx context.Context, in *LikesQuery, opts ...grpc.CallOption) (*LikesSummary, error)
+	// Stream a LikesSummary for each RefType with the given name, ranked by
+	// the requested order. The summaries do not include the individual likes.
+	RankLikes(ctx context.Context, in *RankQuery, opts ...grpc.CallOption) (BeerLikes_RankLikesClient, error)
+	// Fetch the like and dislike counts of a RefType per time bucket.
+	GetLikesHistogram(ctx context.Context, in *HistogramQuery, opts ...grpc.CallOption) (*LikesHistogram, error)
+	// Create a like or dislike for a RefType. The server assigns the id if it
+	// is empty and the created_at if it is not set.
+	CreateLike(ctx context.Context, in *Like, opts ...grpc.CallOption) (*Like, error)
+	// Delete the like with the given id and return it. The like is kept with
+	// its deleted_at set until the server purges it.
+	DeleteLike(ctx context.Context, in *LikeQuery, opts ...grpc.CallOption) (*Like, error)
+	// Restore a deleted like that has not been purged yet and return it.
+	UndeleteLike(ctx context.Context, in *LikeQuery, opts ...grpc.CallOption) (*Like, error)
+	// Update a like and return it with its new version.
+	UpdateLike(ctx context.Context, in *UpdateLikeRequest, opts ...grpc.CallOption) (*Like, error)
+	// Atomically create, flip or delete the like of a user for a RefType to
+	// reach the target state, and return it with the new counts of the RefType.
+	ToggleLike(ctx context.Context, in *ToggleLikeRequest, opts ...grpc.CallOption) (*ToggleLikeResponse, error)
+	// Stream the like events of a RefType, or of every RefType when it is not
+	// set, as they happen. A watcher that falls too far behind is ended with
+	// RESOURCE_EXHAUSTED.
+	WatchLikes(ctx context.Context, in *LikesQuery, opts ...grpc.CallOption) (BeerLikes_WatchLikesClient, error)
+}
+
+type beerLikesClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBeerLikesClient(cc *grpc.ClientConn) BeerLikesClient {
+	return &beerLikesClient{cc}
+}
+
+func (c *beerLikesClient) GetLike(ctx context.Context, in *LikeQuery, opts ...grpc.CallOption) (*Like, error) {
+	out := new(Like)
+	err := c.cc.Invoke(ctx, "/beerlikes.BeerLikes/GetLike", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beerLikesClient) ListLikes(ctx context.Context, in *LikesQuery, opts ...grpc.CallOption) (BeerLikes_ListLikesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BeerLikes_serviceDesc.Streams[0], "/beerlikes.BeerLikes/ListLikes", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &beerLikesListLikesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BeerLikes_ListLikesClient interface {
+	Recv() (*Like, error)
+	grpc.ClientStream
+}
+
+type beerLikesListLikesClient struct {
+	grpc.ClientStream
+}
+
+func (x *beerLikesListLikesClient) Recv() (*Like, error) {
+	m := new(Like)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *beerLikesClient) GetLikesSummary(ctx context.Context, in *LikesQuery, opts ...grpc.CallOption) (*LikesSummary, error) {
+	out := new(LikesSummary)
+	err := c.cc.Invoke(ctx, "/beerlikes.BeerLikes/GetLikesSummary", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beerLikesClient) RankLikes(ctx context.Context, in *RankQuery, opts ...grpc.CallOption) (BeerLikes_RankLikesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BeerLikes_serviceDesc.Streams[1], "/beerlikes.BeerLikes/RankLikes", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &beerLikesRankLikesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BeerLikes_RankLikesClient interface {
+	Recv() (*LikesSummary, error)
+	grpc.ClientStream
+}
+
+type beerLikesRankLikesClient struct {
+	grpc.ClientStream
+}
+
+func (x *beerLikesRankLikesClient) Recv() (*LikesSummary, error) {
+	m := new(LikesSummary)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *beerLikesClient) GetLikesHistogram(ctx context.Context, in *HistogramQuery, opts ...grpc.CallOption) (*LikesHistogram, error) {
+	out := new(LikesHistogram)
+	err := c.cc.Invoke(ctx, "/beerlikes.BeerLikes/GetLikesHistogram", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beerLikesClient) CreateLike(ctx context.Context, in *Like, opts ...grpc.CallOption) (*Like, error) {
+	out := new(Like)
+	err := c.cc.Invoke(ctx, "/beerlikes.BeerLikes/CreateLike", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beerLikesClient) DeleteLike(ctx context.Context, in *LikeQuery, opts ...grpc.CallOption) (*Like, error) {
+	out := new(Like)
+	err := c.cc.Invoke(ctx, "/beerlikes.BeerLikes/DeleteLike", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beerLikesClient) UndeleteLike(ctx context.Context, in *LikeQuery, opts ...grpc.CallOption) (*Like, error) {
+	out := new(Like)
+	err := c.cc.Invoke(ctx, "/beerlikes.BeerLikes/UndeleteLike", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beerLikesClient) UpdateLike(ctx context.Context, in *UpdateLikeRequest, opts ...grpc.CallOption) (*Like, error) {
+	out := new(Like)
+	err := c.cc.Invoke(ctx, "/beerlikes.BeerLikes/UpdateLike", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beerLikesClient) ToggleLike(ctx context.Context, in *ToggleLikeRequest, opts ...grpc.CallOption) (*ToggleLikeResponse, error) {
+	out := new(ToggleLikeResponse)
+	err := c.cc.Invoke(ctx, "/beerlikes.BeerLikes/ToggleLike", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *beerLikesClient) WatchLikes(ctx context.Context, in *LikesQuery, opts ...grpc.CallOption) (BeerLikes_WatchLikesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BeerLikes_serviceDesc.Streams[2], "/beerlikes.BeerLikes/WatchLikes", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &beerLikesWatchLikesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BeerLikes_WatchLikesClient interface {
+	Recv() (*LikeEvent, error)
+	grpc.ClientStream
+}
+
+type beerLikesWatchLikesClient struct {
+	grpc.ClientStream
+}
+
+func (x *beerLikesWatchLikesClient) Recv() (*LikeEvent, error) {
+	m := new(LikeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BeerLikesServer is the server API for BeerLikes service.
+type BeerLikesServer interface {
+	// A simple RPC.
+	//
+	// Obtains the like at a given RefType.
+	//
+	// A like with an empty id is returned if there's no like at the given
+	// reftype. Deleted likes are only returned with include_deleted.
+	GetLike(context.Context, *LikeQuery) (*Like, error)
+	// Stream all the Likes at a given RefType
+	// position. Without a ref_type id, stream the likes of every RefType with
+	// the name, or of the ids with the id_prefix, or of the ids.
+	ListLikes(*LikesQuery, BeerLikes_ListLikesServer) error
+	// Batch fetch all the Likes and let the server do the calculations
+	GetLikesSummary(context.Context, *LikesQuery) (*LikesSummary, error)
+	// Stream a LikesSummary for each RefType with the given name, ranked by
+	// the requested order. The summaries do not include the individual likes.
+	RankLikes(*RankQuery, BeerLikes_RankLikesServer) error
+	// Fetch the like and dislike counts of a RefType per time bucket.
+	GetLikesHistogram(context.Context, *HistogramQuery) (*LikesHistogram, error)
+	// Create a like or dislike for a RefType. The server assigns the id if it
+	// is empty and the created_at if it is not set.
+	CreateLike(context.Context, *Like) (*Like, error)
+	// Delete the like with the given id and return it. The like is kept with
+	// its deleted_at set until the server purges it.
+	DeleteLike(context.Context, *LikeQuery) (*Like, error)
+	// Restore a deleted like that has not been purged yet and return it.
+	UndeleteLike(context.Context, *LikeQuery) (*Like, error)
+	// Update a like and return it with its new version.
+	UpdateLike(context.Context, *UpdateLikeRequest) (*Like, error)
+	// Atomically create, flip or delete the like of a user for a RefType to
+	// reach the target state, and return it with the new counts of the RefType.
+	ToggleLike(context.Context, *ToggleLikeRequest) (*ToggleLikeResponse, error)
+	// Stream the like events of a RefType, or of every RefType when it is not
+	// set, as they happen. A watcher that falls too far behind is ended with
+	// RESOURCE_EXHAUSTED.
+	WatchLikes(*LikesQuery, BeerLikes_WatchLikesServer) error
+}
+
+func RegisterBeerLikesServer(s *grpc.Server, srv BeerLikesServer) {
+	s.RegisterService(&_BeerLikes_serviceDesc, srv)
+}
+
+func _BeerLikes_GetLike_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LikeQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeerLikesServer).GetLike(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beerlikes.BeerLikes/GetLike",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeerLikesServer).GetLike(ctx, req.(*LikeQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BeerLikes_ListLikes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LikesQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BeerLikesServer).ListLikes(m, &beerLikesListLikesServer{stream})
+}
+
+type BeerLikes_ListLikesServer interface {
+	Send(*Like) error
+	grpc.ServerStream
+}
+
+type beerLikesListLikesServer struct {
+	grpc.ServerStream
+}
+
+func (x *beerLikesListLikesServer) Send(m *Like) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BeerLikes_GetLikesSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LikesQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeerLikesServer).GetLikesSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beerlikes.BeerLikes/GetLikesSummary",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeerLikesServer).GetLikesSummary(ctx, req.(*LikesQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BeerLikes_RankLikes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RankQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BeerLikesServer).RankLikes(m, &beerLikesRankLikesServer{stream})
+}
+
+type BeerLikes_RankLikesServer interface {
+	Send(*LikesSummary) error
+	grpc.ServerStream
+}
+
+type beerLikesRankLikesServer struct {
+	grpc.ServerStream
+}
+
+func (x *beerLikesRankLikesServer) Send(m *LikesSummary) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BeerLikes_GetLikesHistogram_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistogramQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeerLikesServer).GetLikesHistogram(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beerlikes.BeerLikes/GetLikesHistogram",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeerLikesServer).GetLikesHistogram(ctx, req.(*HistogramQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BeerLikes_CreateLike_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Like)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeerLikesServer).CreateLike(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beerlikes.BeerLikes/CreateLike",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeerLikesServer).CreateLike(ctx, req.(*Like))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BeerLikes_DeleteLike_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LikeQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeerLikesServer).DeleteLike(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beerlikes.BeerLikes/DeleteLike",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeerLikesServer).DeleteLike(ctx, req.(*LikeQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BeerLikes_UndeleteLike_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LikeQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeerLikesServer).UndeleteLike(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beerlikes.BeerLikes/UndeleteLike",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeerLikesServer).UndeleteLike(ctx, req.(*LikeQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BeerLikes_UpdateLike_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLikeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeerLikesServer).UpdateLike(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beerlikes.BeerLikes/UpdateLike",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeerLikesServer).UpdateLike(ctx, req.(*UpdateLikeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BeerLikes_ToggleLike_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ToggleLikeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BeerLikesServer).ToggleLike(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/beerlikes.BeerLikes/ToggleLike",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BeerLikesServer).ToggleLike(ctx, req.(*ToggleLikeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BeerLikes_WatchLikes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LikesQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BeerLikesServer).WatchLikes(m, &beerLikesWatchLikesServer{stream})
+}
+
+type BeerLikes_WatchLikesServer interface {
+	Send(*LikeEvent) error
+	grpc.ServerStream
+}
+
+type beerLikesWatchLikesServer struct {
+	grpc.ServerStream
+}
+
+func (x *beerLikesWatchLikesServer) Send(m *LikeEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _BeerLikes_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "beerlikes.BeerLikes",
+	HandlerType: (*BeerLikesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLike",
+			Handler:    _BeerLikes_GetLike_Handler,
+		},
+		{
+			MethodName: "GetLikesSummary",
+			Handler:    _BeerLikes_GetLikesSummary_Handler,
+		},
+		{
+			MethodName: "GetLikesHistogram",
+			Handler:    _BeerLikes_GetLikesHistogram_Handler,
+		},
+		{
+			MethodName: "CreateLike",
+			Handler:    _BeerLikes_CreateLike_Handler,
+		},
+		{
+			MethodName: "DeleteLike",
+			Handler:    _BeerLikes_DeleteLike_Handler,
+		},
+		{
+			MethodName: "UndeleteLike",
+			Handler:    _BeerLikes_UndeleteLike_Handler,
+		},
+		{
+			MethodName: "UpdateLike",
+			Handler:    _BeerLikes_UpdateLike_Handler,
+		},
+		{
+			MethodName: "ToggleLike",
+			Handler:    _BeerLikes_ToggleLike_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListLikes",
+			Handler:       _BeerLikes_ListLikes_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RankLikes",
+			Handler:       _BeerLikes_RankLikes_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchLikes",
+			Handler:       _BeerLikes_WatchLikes_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "beer_likes.proto",
+}
 
-// BeerLikesClient is the client API for BeerLikes service.
+// BeerLikesAdminClient is the client API for BeerLikesAdmin service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type BeerLikesClient interface {
-	// A simple RPC.
-	//
-	// Obtains the like at a given RefType.
-	//
-	// A like with an empty id is returned if there's no like at the given
-	// reftype.
-	GetLike(ctx context.Context, in *LikeQuery, opts ...grpc.CallOption) (*Like, error)
-	// Stream all the Likes at a given RefType
-	// position.
-	ListLikes(ctx context.Context, in *LikesQuery, opts ...grpc.CallOption) (BeerLikes_ListLikesClient, error)
-	// Batch fetch all the Likes and let the server do the calculations
-	GetLikesSummary(ctx context.Context, in *LikesQuery, opts ...grpc.CallOption) (*LikesSummary, error)
+type BeerLikesAdminClient interface {
+	// Stream a consistent snapshot of all the likes, encoded in the requested
+	// format. The concatenated chunks make up the whole file.
+	ExportLikes(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (BeerLikesAdmin_ExportLikesClient, error)
+	// Import a stream of likes. An imported like replaces any like with the
+	// same id, so an interrupted import can be sent again. Invalid likes are
+	// rejected and reported in the summary; the others are imported.
+	ImportLikes(ctx context.Context, opts ...grpc.CallOption) (BeerLikesAdmin_ImportLikesClient, error)
+	// Set the parent of a RefType, e.g. the brewery of a beer, or remove it if
+	// the parent is not set. Rollup summaries include the likes of the
+	// descendants of a RefType. A parent that would make a cycle is rejected.
+	SetRefTypeParent(ctx context.Context, in *RefTypeParent, opts ...grpc.CallOption) (*RefTypeParent, error)
+	// Stream the parents of the RefTypes with the given name, or of every
+	// RefType when it is empty.
+	ListRefTypeParents(ctx context.Context, in *RefTypeParentsQuery, opts ...grpc.CallOption) (BeerLikesAdmin_ListRefTypeParentsClient, error)
+	// Stream the counts of likes and requests of each tenant. Unlike the other
+	// calls it is not scoped by the tenant of the request.
+	ListTenants(ctx context.Context, in *TenantsQuery, opts ...grpc.CallOption) (BeerLikesAdmin_ListTenantsClient, error)
+	// Change the log level and format of the server, leaving the empty fields
+	// unchanged, and return the current ones. Like ListTenants it is not scoped
+	// by tenant.
+	SetLogging(ctx context.Context, in *LoggingConfig, opts ...grpc.CallOption) (*LoggingConfig, error)
+	// Stream the audit records of the writes of the tenant of the request as
+	// they are made. It fails with FAILED_PRECONDITION unless the stream audit
+	// sink is enabled.
+	WatchAudit(ctx context.Context, in *AuditQuery, opts ...grpc.CallOption) (BeerLikesAdmin_WatchAuditClient, error)
 }
 
-type beerLikesClient struct {
+type beerLikesAdminClient struct {
 	cc *grpc.ClientConn
 }
 
-func NewBeerLikesClient(cc *grpc.ClientConn) BeerLikesClient {
-	return &beerLikesClient{cc}
+func NewBeerLikesAdminClient(cc *grpc.ClientConn) BeerLikesAdminClient {
+	return &beerLikesAdminClient{cc}
 }
 
-func (c *beerLikesClient) GetLike(ctx context.Context, in *LikeQuery, opts ...grpc.CallOption) (*Like, error) {
-	out := new(Like)
-	err := c.cc.Invoke(ctx, "/beerlikes.BeerLikes/GetLike", in, out, opts...)
+func (c *beerLikesAdminClient) ExportLikes(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (BeerLikesAdmin_ExportLikesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BeerLikesAdmin_serviceDesc.Streams[0], "/beerlikes.BeerLikesAdmin/ExportLikes", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &beerLikesAdminExportLikesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BeerLikesAdmin_ExportLikesClient interface {
+	Recv() (*ExportChunk, error)
+	grpc.ClientStream
+}
+
+type beerLikesAdminExportLikesClient struct {
+	grpc.ClientStream
+}
+
+func (x *beerLikesAdminExportLikesClient) Recv() (*ExportChunk, error) {
+	m := new(ExportChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *beerLikesAdminClient) ImportLikes(ctx context.Context, opts ...grpc.CallOption) (BeerLikesAdmin_ImportLikesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BeerLikesAdmin_serviceDesc.Streams[1], "/beerlikes.BeerLikesAdmin/ImportLikes", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &beerLikesAdminImportLikesClient{stream}
+	return x, nil
+}
+
+type BeerLikesAdmin_ImportLikesClient interface {
+	Send(*Like) error
+	CloseAndRecv() (*ImportSummary, error)
+	grpc.ClientStream
+}
+
+type beerLikesAdminImportLikesClient struct {
+	grpc.ClientStream
+}
+
+func (x *beerLikesAdminImportLikesClient) Send(m *Like) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *beerLikesAdminImportLikesClient) CloseAndRecv() (*ImportSummary, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportSummary)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *beerLikesAdminClient) SetRefTypeParent(ctx context.Context, in *RefTypeParent, opts ...grpc.CallOption) (*RefTypeParent, error) {
+	out := new(RefTypeParent)
+	err := c.cc.Invoke(ctx, "/beerlikes.BeerLikesAdmin/SetRefTypeParent", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *beerLikesClient) ListLikes(ctx context.Context, in *LikesQuery, opts ...grpc.CallOption) (BeerLikes_ListLikesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_BeerLikes_serviceDesc.Streams[0], "/beerlikes.BeerLikes/ListLikes", opts...)
+func (c *beerLikesAdminClient) ListRefTypeParents(ctx context.Context, in *RefTypeParentsQuery, opts ...grpc.CallOption) (BeerLikesAdmin_ListRefTypeParentsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BeerLikesAdmin_serviceDesc.Streams[2], "/beerlikes.BeerLikesAdmin/ListRefTypeParents", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &beerLikesListLikesClient{stream}
+	x := &beerLikesAdminListRefTypeParentsClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -327,155 +2651,460 @@ func (c *beerLikesClient) ListLikes(ctx context.Context, in *LikesQuery, opts ..
 	return x, nil
 }
 
-type BeerLikes_ListLikesClient interface {
-	Recv() (*Like, error)
+type BeerLikesAdmin_ListRefTypeParentsClient interface {
+	Recv() (*RefTypeParent, error)
 	grpc.ClientStream
 }
 
-type beerLikesListLikesClient struct {
+type beerLikesAdminListRefTypeParentsClient struct {
 	grpc.ClientStream
 }
 
-func (x *beerLikesListLikesClient) Recv() (*Like, error) {
-	m := new(Like)
+func (x *beerLikesAdminListRefTypeParentsClient) Recv() (*RefTypeParent, error) {
+	m := new(RefTypeParent)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *beerLikesClient) GetLikesSummary(ctx context.Context, in *LikesQuery, opts ...grpc.CallOption) (*LikesSummary, error) {
-	out := new(LikesSummary)
-	err := c.cc.Invoke(ctx, "/beerlikes.BeerLikes/GetLikesSummary", in, out, opts...)
+func (c *beerLikesAdminClient) ListTenants(ctx context.Context, in *TenantsQuery, opts ...grpc.CallOption) (BeerLikesAdmin_ListTenantsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BeerLikesAdmin_serviceDesc.Streams[3], "/beerlikes.BeerLikesAdmin/ListTenants", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &beerLikesAdminListTenantsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BeerLikesAdmin_ListTenantsClient interface {
+	Recv() (*TenantStats, error)
+	grpc.ClientStream
+}
+
+type beerLikesAdminListTenantsClient struct {
+	grpc.ClientStream
+}
+
+func (x *beerLikesAdminListTenantsClient) Recv() (*TenantStats, error) {
+	m := new(TenantStats)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *beerLikesAdminClient) SetLogging(ctx context.Context, in *LoggingConfig, opts ...grpc.CallOption) (*LoggingConfig, error) {
+	out := new(LoggingConfig)
+	err := c.cc.Invoke(ctx, "/beerlikes.BeerLikesAdmin/SetLogging", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// BeerLikesServer is the server API for BeerLikes service.
-type BeerLikesServer interface {
-	// A simple RPC.
-	//
-	// Obtains the like at a given RefType.
-	//
-	// A like with an empty id is returned if there's no like at the given
-	// reftype.
-	GetLike(context.Context, *LikeQuery) (*Like, error)
-	// Stream all the Likes at a given RefType
-	// position.
-	ListLikes(*LikesQuery, BeerLikes_ListLikesServer) error
-	// Batch fetch all the Likes and let the server do the calculations
-	GetLikesSummary(context.Context, *LikesQuery) (*LikesSummary, error)
+func (c *beerLikesAdminClient) WatchAudit(ctx context.Context, in *AuditQuery, opts ...grpc.CallOption) (BeerLikesAdmin_WatchAuditClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BeerLikesAdmin_serviceDesc.Streams[4], "/beerlikes.BeerLikesAdmin/WatchAudit", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &beerLikesAdminWatchAuditClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-func RegisterBeerLikesServer(s *grpc.Server, srv BeerLikesServer) {
-	s.RegisterService(&_BeerLikes_serviceDesc, srv)
+type BeerLikesAdmin_WatchAuditClient interface {
+	Recv() (*AuditRecord, error)
+	grpc.ClientStream
 }
 
-func _BeerLikes_GetLike_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LikeQuery)
+type beerLikesAdminWatchAuditClient struct {
+	grpc.ClientStream
+}
+
+func (x *beerLikesAdminWatchAuditClient) Recv() (*AuditRecord, error) {
+	m := new(AuditRecord)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BeerLikesAdminServer is the server API for BeerLikesAdmin service.
+type BeerLikesAdminServer interface {
+	// Stream a consistent snapshot of all the likes, encoded in the requested
+	// format. The concatenated chunks make up the whole file.
+	ExportLikes(*ExportRequest, BeerLikesAdmin_ExportLikesServer) error
+	// Import a stream of likes. An imported like replaces any like with the
+	// same id, so an interrupted import can be sent again. Invalid likes are
+	// rejected and reported in the summary; the others are imported.
+	ImportLikes(BeerLikesAdmin_ImportLikesServer) error
+	// Set the parent of a RefType, e.g. the brewery of a beer, or remove it if
+	// the parent is not set. Rollup summaries include the likes of the
+	// descendants of a RefType. A parent that would make a cycle is rejected.
+	SetRefTypeParent(context.Context, *RefTypeParent) (*RefTypeParent, error)
+	// Stream the parents of the RefTypes with the given name, or of every
+	// RefType when it is empty.
+	ListRefTypeParents(*RefTypeParentsQuery, BeerLikesAdmin_ListRefTypeParentsServer) error
+	// Stream the counts of likes and requests of each tenant. Unlike the other
+	// calls it is not scoped by the tenant of the request.
+	ListTenants(*TenantsQuery, BeerLikesAdmin_ListTenantsServer) error
+	// Change the log level and format of the server, leaving the empty fields
+	// unchanged, and return the current ones. Like ListTenants it is not scoped
+	// by tenant.
+	SetLogging(context.Context, *LoggingConfig) (*LoggingConfig, error)
+	// Stream the audit records of the writes of the tenant of the request as
+	// they are made. It fails with FAILED_PRECONDITION unless the stream audit
+	// sink is enabled.
+	WatchAudit(*AuditQuery, BeerLikesAdmin_WatchAuditServer) error
+}
+
+func RegisterBeerLikesAdminServer(s *grpc.Server, srv BeerLikesAdminServer) {
+	s.RegisterService(&_BeerLikesAdmin_serviceDesc, srv)
+}
+
+func _BeerLikesAdmin_ExportLikes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BeerLikesAdminServer).ExportLikes(m, &beerLikesAdminExportLikesServer{stream})
+}
+
+type BeerLikesAdmin_ExportLikesServer interface {
+	Send(*ExportChunk) error
+	grpc.ServerStream
+}
+
+type beerLikesAdminExportLikesServer struct {
+	grpc.ServerStream
+}
+
+func (x *beerLikesAdminExportLikesServer) Send(m *ExportChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BeerLikesAdmin_ImportLikes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BeerLikesAdminServer).ImportLikes(&beerLikesAdminImportLikesServer{stream})
+}
+
+type BeerLikesAdmin_ImportLikesServer interface {
+	SendAndClose(*ImportSummary) error
+	Recv() (*Like, error)
+	grpc.ServerStream
+}
+
+type beerLikesAdminImportLikesServer struct {
+	grpc.ServerStream
+}
+
+func (x *beerLikesAdminImportLikesServer) SendAndClose(m *ImportSummary) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *beerLikesAdminImportLikesServer) Recv() (*Like, error) {
+	m := new(Like)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _BeerLikesAdmin_SetRefTypeParent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefTypeParent)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BeerLikesServer).GetLike(ctx, in)
+		return srv.(BeerLikesAdminServer).SetRefTypeParent(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/beerlikes.BeerLikes/GetLike",
+		FullMethod: "/beerlikes.BeerLikesAdmin/SetRefTypeParent",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BeerLikesServer).GetLike(ctx, req.(*LikeQuery))
+		return srv.(BeerLikesAdminServer).SetRefTypeParent(ctx, req.(*RefTypeParent))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BeerLikes_ListLikes_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(LikesQuery)
+func _BeerLikesAdmin_ListRefTypeParents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RefTypeParentsQuery)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(BeerLikesServer).ListLikes(m, &beerLikesListLikesServer{stream})
+	return srv.(BeerLikesAdminServer).ListRefTypeParents(m, &beerLikesAdminListRefTypeParentsServer{stream})
 }
 
-type BeerLikes_ListLikesServer interface {
-	Send(*Like) error
+type BeerLikesAdmin_ListRefTypeParentsServer interface {
+	Send(*RefTypeParent) error
 	grpc.ServerStream
 }
 
-type beerLikesListLikesServer struct {
+type beerLikesAdminListRefTypeParentsServer struct {
 	grpc.ServerStream
 }
 
-func (x *beerLikesListLikesServer) Send(m *Like) error {
+func (x *beerLikesAdminListRefTypeParentsServer) Send(m *RefTypeParent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _BeerLikes_GetLikesSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LikesQuery)
+func _BeerLikesAdmin_ListTenants_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TenantsQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BeerLikesAdminServer).ListTenants(m, &beerLikesAdminListTenantsServer{stream})
+}
+
+type BeerLikesAdmin_ListTenantsServer interface {
+	Send(*TenantStats) error
+	grpc.ServerStream
+}
+
+type beerLikesAdminListTenantsServer struct {
+	grpc.ServerStream
+}
+
+func (x *beerLikesAdminListTenantsServer) Send(m *TenantStats) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BeerLikesAdmin_SetLogging_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoggingConfig)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BeerLikesServer).GetLikesSummary(ctx, in)
+		return srv.(BeerLikesAdminServer).SetLogging(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/beerlikes.BeerLikes/GetLikesSummary",
+		FullMethod: "/beerlikes.BeerLikesAdmin/SetLogging",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BeerLikesServer).GetLikesSummary(ctx, req.(*LikesQuery))
+		return srv.(BeerLikesAdminServer).SetLogging(ctx, req.(*LoggingConfig))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _BeerLikes_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "beerlikes.BeerLikes",
-	HandlerType: (*BeerLikesServer)(nil),
+func _BeerLikesAdmin_WatchAudit_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AuditQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BeerLikesAdminServer).WatchAudit(m, &beerLikesAdminWatchAuditServer{stream})
+}
+
+type BeerLikesAdmin_WatchAuditServer interface {
+	Send(*AuditRecord) error
+	grpc.ServerStream
+}
+
+type beerLikesAdminWatchAuditServer struct {
+	grpc.ServerStream
+}
+
+func (x *beerLikesAdminWatchAuditServer) Send(m *AuditRecord) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _BeerLikesAdmin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "beerlikes.BeerLikesAdmin",
+	HandlerType: (*BeerLikesAdminServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "GetLike",
-			Handler:    _BeerLikes_GetLike_Handler,
+			MethodName: "SetRefTypeParent",
+			Handler:    _BeerLikesAdmin_SetRefTypeParent_Handler,
 		},
 		{
-			MethodName: "GetLikesSummary",
-			Handler:    _BeerLikes_GetLikesSummary_Handler,
+			MethodName: "SetLogging",
+			Handler:    _BeerLikesAdmin_SetLogging_Handler,
 		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
-			StreamName:    "ListLikes",
-			Handler:       _BeerLikes_ListLikes_Handler,
+			StreamName:    "ExportLikes",
+			Handler:       _BeerLikesAdmin_ExportLikes_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportLikes",
+			Handler:       _BeerLikesAdmin_ImportLikes_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ListRefTypeParents",
+			Handler:       _BeerLikesAdmin_ListRefTypeParents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListTenants",
+			Handler:       _BeerLikesAdmin_ListTenants_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchAudit",
+			Handler:       _BeerLikesAdmin_WatchAudit_Handler,
 			ServerStreams: true,
 		},
 	},
 	Metadata: "beer_likes.proto",
 }
 
-func init() { proto.RegisterFile("beer_likes.proto", fileDescriptor_beer_likes_147e708ecc268ceb) }
-
-var fileDescriptor_beer_likes_147e708ecc268ceb = []byte{
-	// 325 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0x3b, 0x4f, 0x02, 0x41,
-	0x10, 0x66, 0x79, 0x08, 0x37, 0x10, 0x30, 0x13, 0x8c, 0x17, 0x69, 0xf0, 0x12, 0x13, 0x1a, 0xd0,
-	0x9c, 0x85, 0x85, 0x1d, 0x16, 0x36, 0x16, 0x78, 0xd2, 0x59, 0x90, 0xc3, 0x1b, 0x74, 0x03, 0x07,
-	0x9b, 0xdd, 0xa5, 0xb8, 0x1f, 0xe7, 0x7f, 0x33, 0xfb, 0xc8, 0x69, 0x2e, 0xa1, 0xb0, 0xdb, 0xfd,
-	0xe6, 0x7b, 0xcc, 0xb7, 0x59, 0x38, 0x5f, 0x13, 0xc9, 0xd5, 0x8e, 0x6f, 0x49, 0xcd, 0x84, 0x3c,
-	0xe8, 0x03, 0x06, 0x06, 0xb1, 0x40, 0x34, 0x85, 0x76, 0x42, 0x9b, 0x65, 0x21, 0x08, 0x11, 0x9a,
-	0xfb, 0x34, 0xa7, 0x90, 0x8d, 0xd9, 0x24, 0x48, 0xec, 0x19, 0xfb, 0x50, 0xe7, 0x59, 0x58, 0xb7,
-	0x48, 0x9d, 0x67, 0xd1, 0x3b, 0x34, 0x5f, 0xf8, 0x96, 0x70, 0x0a, 0x1d, 0x49, 0x9b, 0x95, 0x2e,
-	0x84, 0xe3, 0x77, 0x63, 0x9c, 0x95, 0xa6, 0x33, 0xef, 0x98, 0xb4, 0xa5, 0xb7, 0xae, 0xd8, 0xe0,
-	0x10, 0x5a, 0x86, 0x99, 0x85, 0x8d, 0x31, 0x9b, 0x74, 0x12, 0x77, 0x89, 0x46, 0x10, 0x18, 0xf3,
-	0xd7, 0x23, 0xc9, 0xc2, 0x4b, 0x58, 0x99, 0xfc, 0x08, 0x60, 0x86, 0xca, 0x4d, 0xff, 0x97, 0x1f,
-	0xed, 0xa1, 0x67, 0xc5, 0x6f, 0xc7, 0x3c, 0x4f, 0x65, 0x81, 0x37, 0x2e, 0x5f, 0x85, 0x6c, 0xdc,
-	0x98, 0x74, 0xe3, 0xc1, 0x1f, 0xad, 0xe1, 0xb9, 0x85, 0x94, 0x59, 0x53, 0x1f, 0x74, 0xba, 0xb3,
-	0x9b, 0xb7, 0x12, 0x77, 0xc1, 0x6b, 0xe8, 0xd1, 0x2e, 0x15, 0x8a, 0xb2, 0x95, 0xe6, 0x39, 0xd9,
-	0x0e, 0xcd, 0xa4, 0xeb, 0xb1, 0x25, 0xcf, 0x29, 0xfe, 0x66, 0x10, 0xcc, 0x89, 0xa4, 0x0d, 0xc5,
-	0x18, 0xda, 0xcf, 0xa4, 0xed, 0xbb, 0x0d, 0x2b, 0x49, 0xb6, 0xcd, 0x55, 0x35, 0x3f, 0xaa, 0xe1,
-	0x83, 0x79, 0x0b, 0xa5, 0x9d, 0xc1, 0x45, 0x65, 0xae, 0x4e, 0xc9, 0xee, 0x18, 0x3e, 0xc1, 0xc0,
-	0x87, 0x95, 0x6d, 0x4f, 0xc8, 0x2f, 0xab, 0xb0, 0xe7, 0x47, 0xb5, 0xf9, 0x2d, 0x8c, 0xc4, 0x97,
-	0xe4, 0xea, 0x23, 0xfd, 0x24, 0xcb, 0x4a, 0x85, 0xf8, 0x65, 0xcf, 0xfb, 0x65, 0xb7, 0x85, 0xf9,
-	0x4f, 0x0b, 0xb6, 0x3e, 0xb3, 0x1f, 0xeb, 0xfe, 0x27, 0x00, 0x00, 0xff, 0xff, 0x15, 0xa6, 0xbd,
-	0x86, 0x6c, 0x02, 0x00, 0x00,
+func init() { proto.RegisterFile("beer_likes.proto", fileDescriptor_beer_likes_13313009400b234e) }
+
+var fileDescriptor_beer_likes_13313009400b234e = []byte{
+	// 2240 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x58, 0xdd, 0x72, 0xdb, 0xc6,
+	0xf5, 0x17, 0xc0, 0xef, 0x43, 0x8a, 0x82, 0x37, 0xfe, 0x3b, 0x08, 0x13, 0xff, 0x2d, 0xc3, 0x49,
+	0xa3, 0xa4, 0x0d, 0xad, 0xaa, 0xcd, 0x38, 0x8e, 0xeb, 0x78, 0x28, 0x8a, 0xb6, 0x55, 0x33, 0x96,
+	0xba, 0xa4, 0x9d, 0xc9, 0x15, 0x07, 0x22, 0x96, 0x14, 0x22, 0x12, 0x40, 0x81, 0xa5, 0xc6, 0xca,
+	0x2b, 0x74, 0x3a, 0xd3, 0xdb, 0xf6, 0x09, 0x7a, 0xdb, 0x5e, 0xb4, 0x4f, 0xd2, 0x8b, 0x4e, 0x67,
+	0xda, 0xf7, 0xe8, 0x55, 0x67, 0xcf, 0x2e, 0x40, 0x80, 0x1f, 0x92, 0xe5, 0x2b, 0xee, 0x39, 0x7b,
+	0x76, 0x71, 0xce, 0xd9, 0xdf, 0xf9, 0x22, 0x18, 0x27, 0x8c, 0x85, 0x83, 0x89, 0x7b, 0xc6, 0xa2,
+	0x66, 0x10, 0xfa, 0xdc, 0x27, 0x15, 0xc1, 0x41, 0x46, 0x63, 0x7b, 0xec, 0xfb, 0xe3, 0x09, 0xbb,
+	0x8f, 0x1b, 0x27, 0xb3, 0xd1, 0xfd, 0x91, 0xcb, 0x26, 0xce, 0x60, 0x6a, 0x47, 0x67, 0x52, 0xb8,
+	0x71, 0x67, 0x51, 0x82, 0xbb, 0x53, 0x16, 0x71, 0x7b, 0x1a, 0x48, 0x01, 0xeb, 0x0b, 0x28, 0x51,
+	0x36, 0xea, 0x5f, 0x04, 0x8c, 0x10, 0xc8, 0x7b, 0xf6, 0x94, 0x99, 0xda, 0xb6, 0xb6, 0x53, 0xa1,
+	0xb8, 0x26, 0x75, 0xd0, 0x5d, 0xc7, 0xd4, 0x91, 0xa3, 0xbb, 0x8e, 0xf5, 0x03, 0x6c, 0x2a, 0xf1,
+	0x63, 0x3b, 0x64, 0x1e, 0x27, 0x5f, 0x40, 0x39, 0x64, 0xa3, 0x01, 0xbf, 0x08, 0xe4, 0xc1, 0xea,
+	0x1e, 0x69, 0x26, 0x0a, 0x36, 0x95, 0x2c, 0x2d, 0x85, 0xea, 0x1b, 0x9f, 0x43, 0x31, 0xc0, 0x83,
+	0xa6, 0xbe, 0x56, 0x58, 0x49, 0x58, 0x9f, 0xc1, 0x7b, 0x99, 0x6f, 0x45, 0xbf, 0x99, 0xb1, 0xf0,
+	0x62, 0x95, 0x9a, 0xd6, 0xdf, 0x74, 0xc8, 0x77, 0xdd, 0x33, 0x76, 0x5d, 0x75, 0x16, 0xcc, 0x23,
+	0x37, 0xa1, 0x20, 0x24, 0x1d, 0x33, 0xb7, 0xad, 0xed, 0x94, 0xa9, 0x24, 0xc8, 0x43, 0x80, 0x61,
+	0xc8, 0x6c, 0xce, 0x9c, 0x81, 0xcd, 0xcd, 0x3c, 0x5e, 0xdb, 0x68, 0x4a, 0xcf, 0x36, 0x63, 0xcf,
+	0x36, 0xfb, 0xb1, 0x67, 0x69, 0x45, 0x49, 0xb7, 0x38, 0x31, 0xa1, 0x74, 0xce, 0xc2, 0xc8, 0xf5,
+	0x3d, 0xb3, 0xb0, 0xad, 0xed, 0xe4, 0x69, 0x4c, 0x8a, 0x4b, 0x1d, 0x36, 0x61, 0xea, 0xd2, 0xe2,
+	0xd5, 0x97, 0x2a, 0xe9, 0x16, 0x27, 0xef, 0x43, 0x69, 0x16, 0xb1, 0x70, 0xe0, 0x3a, 0x66, 0x09,
+	0x55, 0x2f, 0x0a, 0xf2, 0xd0, 0x21, 0x0d, 0x61, 0xbd, 0x3d, 0xe4, 0xe2, 0x73, 0x65, 0xdc, 0x49,
+	0x68, 0x61, 0xda, 0xb9, 0x3d, 0x99, 0x31, 0xb3, 0xb2, 0xad, 0xed, 0x14, 0xa8, 0x24, 0xac, 0x3f,
+	0x6b, 0x50, 0x11, 0x8e, 0x93, 0xae, 0x95, 0xee, 0xd0, 0x12, 0x77, 0x7c, 0x06, 0x06, 0x7b, 0x13,
+	0xb0, 0xa1, 0x50, 0x32, 0x36, 0x43, 0x47, 0x33, 0xb6, 0x62, 0xfe, 0x6b, 0x65, 0xce, 0xa7, 0xb0,
+	0xe5, 0x7a, 0xc3, 0xc9, 0xcc, 0x61, 0x03, 0xa5, 0xa8, 0xf2, 0x61, 0x5d, 0xb1, 0x0f, 0x24, 0x97,
+	0x3c, 0x80, 0x4a, 0xc8, 0x6c, 0x09, 0xd2, 0xb5, 0xbe, 0x7c, 0x2a, 0x70, 0xfc, 0xad, 0x1d, 0x9d,
+	0xa1, 0x01, 0xb8, 0xb2, 0xfe, 0xa9, 0xc1, 0x8d, 0x57, 0x81, 0x63, 0x73, 0x26, 0x14, 0xa6, 0xec,
+	0xb7, 0x33, 0x16, 0x71, 0x72, 0x0f, 0xf2, 0xe2, 0x91, 0xd4, 0x63, 0x6f, 0xa5, 0x1e, 0x1b, 0xa5,
+	0x70, 0xf3, 0x3a, 0x76, 0x3c, 0x82, 0xea, 0x0c, 0x3f, 0x22, 0x15, 0xcc, 0x5d, 0xa9, 0x20, 0x48,
+	0x71, 0xb1, 0x7e, 0x77, 0xdb, 0x7e, 0xaf, 0xc3, 0x8d, 0xbe, 0x3f, 0x1e, 0x4f, 0x32, 0xb6, 0xa5,
+	0xde, 0x59, 0xcb, 0xbc, 0x73, 0x1a, 0xe5, 0xfa, 0xd5, 0x28, 0x7f, 0x04, 0x45, 0x6e, 0x87, 0x63,
+	0xc6, 0xd1, 0x9c, 0xfa, 0xde, 0xbd, 0x94, 0xf0, 0xd2, 0x57, 0x9b, 0x7d, 0x14, 0xa5, 0xea, 0xc8,
+	0xbb, 0xdb, 0xf4, 0x35, 0x14, 0xe5, 0x55, 0x04, 0xa0, 0xd8, 0x3f, 0x7a, 0xf6, 0xac, 0xdb, 0x31,
+	0x36, 0x48, 0x05, 0x0a, 0xdd, 0xc3, 0x17, 0x9d, 0x03, 0x43, 0x23, 0x35, 0x28, 0x1f, 0x1c, 0xf6,
+	0x24, 0xa5, 0x93, 0x2a, 0x94, 0xda, 0xdd, 0x4e, 0x8b, 0x76, 0x0e, 0x8c, 0x9c, 0x35, 0x01, 0x92,
+	0x56, 0x2c, 0x0a, 0x7c, 0x2f, 0x62, 0x6f, 0xf7, 0xd6, 0x3f, 0x87, 0x52, 0x34, 0x9b, 0x4e, 0xed,
+	0xf0, 0x42, 0xb9, 0xe6, 0xfd, 0x05, 0xb9, 0xa8, 0x27, 0xb7, 0x69, 0x2c, 0x67, 0xfd, 0xa3, 0x00,
+	0x80, 0x3b, 0x32, 0x0a, 0xae, 0x99, 0x43, 0x7e, 0x06, 0xc4, 0x1d, 0xc5, 0xb0, 0x1a, 0x0c, 0x4f,
+	0x6d, 0x6f, 0xcc, 0x1c, 0x05, 0x2f, 0xc3, 0x1d, 0x29, 0x60, 0xb5, 0x25, 0xff, 0xed, 0xe3, 0xe4,
+	0x16, 0x14, 0x43, 0x7f, 0x32, 0x99, 0x05, 0xe8, 0xf4, 0x32, 0x55, 0x14, 0xf9, 0x10, 0x2a, 0xae,
+	0x33, 0x08, 0x42, 0x36, 0x72, 0xdf, 0x60, 0x4e, 0xa9, 0xd0, 0xb2, 0xeb, 0x1c, 0x23, 0x4d, 0x0c,
+	0xc8, 0xb9, 0x4e, 0x64, 0x16, 0xb7, 0x73, 0x3b, 0x15, 0x2a, 0x96, 0xe4, 0x41, 0x9c, 0xd1, 0x4a,
+	0xf8, 0xf4, 0x77, 0x17, 0x9d, 0x81, 0x26, 0xe3, 0xd2, 0x79, 0xea, 0x4e, 0x38, 0x0b, 0xe3, 0xa4,
+	0x97, 0x02, 0x5f, 0x39, 0x03, 0xbe, 0x27, 0xb0, 0x99, 0x64, 0xc3, 0x11, 0x67, 0xa1, 0x59, 0x59,
+	0x03, 0x8a, 0x79, 0xee, 0xaa, 0xc5, 0x09, 0x51, 0xc8, 0x93, 0x16, 0xd4, 0xe3, 0x0b, 0x4e, 0xd8,
+	0xc8, 0x0f, 0x99, 0x09, 0x57, 0xde, 0x10, 0x7f, 0x72, 0x1f, 0x0f, 0x90, 0x0f, 0xa0, 0x2c, 0xb4,
+	0x1c, 0x08, 0x63, 0xab, 0x68, 0x6c, 0x49, 0xd0, 0x87, 0x4e, 0x44, 0xbe, 0x82, 0xb2, 0x1f, 0x3a,
+	0x2c, 0x1c, 0x9c, 0x5c, 0x98, 0x35, 0xb4, 0xf9, 0xf6, 0x6a, 0x9b, 0x8f, 0x84, 0xd4, 0xfe, 0x05,
+	0x2d, 0xf9, 0x72, 0x21, 0x93, 0xff, 0xd4, 0xe5, 0xe6, 0xa6, 0xcc, 0x90, 0x48, 0x64, 0xf1, 0x5f,
+	0xbf, 0x06, 0xfe, 0x1f, 0x42, 0x35, 0xe5, 0x56, 0x52, 0x82, 0x5c, 0xab, 0xdb, 0x35, 0x36, 0x48,
+	0x1d, 0x00, 0x31, 0x3f, 0x38, 0x7a, 0xd9, 0xfd, 0xde, 0xd0, 0xc8, 0x0d, 0xd8, 0x8c, 0xc3, 0x40,
+	0xb2, 0x74, 0xab, 0x03, 0x25, 0xa5, 0x9d, 0x88, 0x97, 0x5e, 0xeb, 0x75, 0xe7, 0xc0, 0xd8, 0x20,
+	0x04, 0xea, 0x6d, 0xda, 0x69, 0xf5, 0x3b, 0x07, 0x83, 0x56, 0x7f, 0xd0, 0xea, 0xb5, 0x0d, 0x8d,
+	0xbc, 0x07, 0x5b, 0x29, 0xde, 0x41, 0xa7, 0xd7, 0x36, 0x74, 0x52, 0x04, 0xfd, 0x50, 0x44, 0xd1,
+	0x5f, 0x35, 0xa8, 0x50, 0xdb, 0x3b, 0x5b, 0x5b, 0x37, 0xc9, 0x83, 0x94, 0xb3, 0x74, 0x74, 0xd6,
+	0x47, 0x69, 0xa8, 0xc7, 0x67, 0x2f, 0xf1, 0x55, 0x2e, 0xed, 0xab, 0x35, 0x98, 0xb5, 0x3e, 0xcf,
+	0xd8, 0xd3, 0x3f, 0xea, 0xb7, 0xba, 0x32, 0x15, 0xf4, 0xda, 0x47, 0xb4, 0x63, 0x68, 0x62, 0x49,
+	0x5b, 0xfd, 0xc3, 0x23, 0x43, 0xb7, 0xfe, 0x95, 0x87, 0x5a, 0x3a, 0x4c, 0xc9, 0x27, 0x12, 0xc1,
+	0x91, 0xa9, 0x6d, 0xe7, 0x56, 0x85, 0xbd, 0xdc, 0x15, 0x1a, 0x71, 0x9f, 0xdb, 0x13, 0xb4, 0xa3,
+	0x40, 0x25, 0x41, 0xee, 0x42, 0x8d, 0x4d, 0xec, 0x20, 0x62, 0xce, 0x40, 0x74, 0x3e, 0xa8, 0x6e,
+	0x9e, 0x56, 0x15, 0x4f, 0xe0, 0x2b, 0x13, 0xee, 0xf9, 0xab, 0xc3, 0xfd, 0x36, 0x00, 0x42, 0x6f,
+	0xe8, 0xcf, 0x3c, 0x8e, 0x01, 0x58, 0xa0, 0x15, 0xc1, 0x69, 0x0b, 0x06, 0xb9, 0x07, 0x9b, 0x8e,
+	0x1b, 0xa5, 0x24, 0x8a, 0x28, 0x51, 0x53, 0x4c, 0x29, 0x14, 0xdf, 0x11, 0xda, 0xdc, 0xf5, 0x31,
+	0x32, 0x35, 0x79, 0x07, 0x15, 0x0c, 0x61, 0x4a, 0x34, 0x14, 0x71, 0x51, 0xc6, 0x1d, 0x49, 0xa4,
+	0x5b, 0x89, 0x4a, 0xb6, 0x95, 0xb8, 0x0b, 0x35, 0xcf, 0xe7, 0x83, 0xa9, 0xef, 0xb8, 0x23, 0x97,
+	0x39, 0x18, 0x4e, 0x65, 0x5a, 0xf5, 0x7c, 0xfe, 0xad, 0x62, 0xa1, 0x5a, 0xaa, 0xdb, 0x90, 0x6a,
+	0x55, 0x95, 0x5a, 0x92, 0x29, 0xd5, 0xea, 0xc3, 0x56, 0xdc, 0x2e, 0x48, 0xa9, 0xc8, 0xac, 0xa1,
+	0xcf, 0x7f, 0xba, 0x26, 0x85, 0x36, 0xa9, 0x12, 0xc7, 0xf3, 0x51, 0xc7, 0xe3, 0xe1, 0x05, 0xad,
+	0x87, 0x19, 0xa6, 0xd0, 0x4e, 0xd8, 0xe9, 0x8d, 0xd5, 0x97, 0x65, 0x74, 0x55, 0x25, 0x4f, 0x7e,
+	0xf8, 0x13, 0xa8, 0xdb, 0xe7, 0x2c, 0xb4, 0xc7, 0xd2, 0x25, 0xde, 0x18, 0x03, 0x4d, 0xa3, 0x9b,
+	0x8a, 0x4b, 0x91, 0xd9, 0x68, 0x89, 0x86, 0x70, 0xe9, 0x83, 0x22, 0xe9, 0x9d, 0xb1, 0x0b, 0x85,
+	0x6b, 0xb1, 0x9c, 0xf7, 0x3a, 0x7a, 0xaa, 0xd7, 0xf9, 0x5a, 0xff, 0x4a, 0xb3, 0xfe, 0xa8, 0x43,
+	0xfd, 0xb9, 0x1b, 0x71, 0x7f, 0x1c, 0xda, 0xd3, 0x77, 0x4a, 0xf7, 0x0f, 0x01, 0x22, 0x6e, 0x87,
+	0x5c, 0xe2, 0x49, 0xbf, 0xba, 0x6f, 0x43, 0x69, 0x41, 0x93, 0x2f, 0xa1, 0xcc, 0xbc, 0x14, 0x10,
+	0x2f, 0x3f, 0x58, 0x62, 0x9e, 0x04, 0x68, 0x07, 0xaa, 0x27, 0xb3, 0xe1, 0x19, 0xe3, 0x83, 0xc8,
+	0xfd, 0x51, 0x62, 0xb4, 0xbe, 0xf7, 0x71, 0x4a, 0xc7, 0xac, 0x41, 0xcd, 0x7d, 0x14, 0xee, 0xb9,
+	0x3f, 0x32, 0x0a, 0x27, 0xc9, 0xda, 0xba, 0x03, 0x30, 0xdf, 0x11, 0xe9, 0xe8, 0xa0, 0xf5, 0xbd,
+	0xb1, 0x41, 0xca, 0x90, 0xff, 0xae, 0xd3, 0x79, 0x61, 0x68, 0xd6, 0x1f, 0x34, 0xd8, 0x4a, 0xae,
+	0x92, 0xa2, 0x0b, 0xd6, 0x6a, 0xd7, 0xb1, 0x36, 0x1b, 0x28, 0xfa, 0x95, 0x81, 0x92, 0x5b, 0x0e,
+	0x14, 0xeb, 0x3f, 0x1a, 0xd4, 0x11, 0x70, 0x89, 0x5e, 0xd7, 0x7d, 0xae, 0x05, 0xe7, 0xe9, 0xef,
+	0xe6, 0x3c, 0xf2, 0x4b, 0x28, 0x49, 0x2a, 0x32, 0x73, 0x18, 0x12, 0x8d, 0x55, 0x57, 0xc8, 0xc3,
+	0x34, 0x16, 0x5d, 0xca, 0x3e, 0xf9, 0xa5, 0xec, 0x63, 0xfd, 0x5b, 0x97, 0x0d, 0x78, 0xe7, 0x5c,
+	0x4e, 0x53, 0xf9, 0xc4, 0xb0, 0xfa, 0xde, 0x07, 0x0b, 0x61, 0x87, 0x32, 0x4d, 0xb4, 0x0f, 0xc5,
+	0x44, 0xbf, 0x1f, 0x89, 0xae, 0xcd, 0x1b, 0x32, 0xd5, 0x70, 0x24, 0x34, 0x69, 0x42, 0xfe, 0x2d,
+	0x81, 0x86, 0x72, 0x49, 0x73, 0x95, 0xbf, 0xac, 0xb9, 0xda, 0x4d, 0xc6, 0xb7, 0x02, 0x8a, 0x99,
+	0xcb, 0xae, 0x97, 0xb3, 0x5a, 0x3c, 0xc4, 0x91, 0x6f, 0xe0, 0x46, 0x10, 0xb2, 0x73, 0xd7, 0x9f,
+	0x45, 0x83, 0xe4, 0xdd, 0x8a, 0x6b, 0xdf, 0x6d, 0x2b, 0x16, 0x56, 0x0c, 0xeb, 0x09, 0xe4, 0xc5,
+	0xaf, 0x80, 0xa9, 0x28, 0x91, 0xc6, 0x86, 0xe8, 0x26, 0x5f, 0xbd, 0xc4, 0xb5, 0x86, 0xeb, 0xe3,
+	0x83, 0x56, 0xbf, 0x63, 0xe8, 0xa2, 0x86, 0x1c, 0xbf, 0xa2, 0xcf, 0x3a, 0x46, 0x4e, 0xb0, 0x8f,
+	0x5b, 0xb4, 0xf3, 0xb2, 0x6f, 0xe4, 0xad, 0x00, 0x36, 0x3b, 0x6f, 0x02, 0x3f, 0xe4, 0x71, 0x57,
+	0xfd, 0x00, 0x8a, 0x23, 0x3f, 0x9c, 0xda, 0x5c, 0x79, 0xf9, 0x4e, 0x4a, 0x8d, 0x8c, 0x64, 0xf3,
+	0x29, 0x8a, 0x51, 0x25, 0x6e, 0xfd, 0x04, 0x8a, 0x92, 0x23, 0x94, 0xf9, 0x75, 0xef, 0xe8, 0xa5,
+	0xac, 0x61, 0x62, 0xd5, 0x35, 0x34, 0x11, 0x51, 0xed, 0xde, 0x6b, 0x43, 0xb7, 0xee, 0x42, 0x55,
+	0xde, 0xd3, 0x3e, 0x9d, 0x79, 0x67, 0xa2, 0xee, 0x3a, 0x36, 0xb7, 0xf1, 0x6b, 0x35, 0x8a, 0x6b,
+	0xeb, 0x4f, 0x1a, 0x6c, 0x1e, 0x4e, 0x85, 0x4c, 0x5c, 0xe5, 0x1a, 0x50, 0x76, 0x91, 0xc1, 0x64,
+	0xb3, 0x5f, 0xa0, 0x09, 0x4d, 0x70, 0xac, 0xfb, 0x01, 0xc7, 0x14, 0x15, 0x47, 0x09, 0x4d, 0x9a,
+	0x50, 0x64, 0x61, 0xe8, 0x87, 0x31, 0x2e, 0x6f, 0xa5, 0xac, 0x91, 0x5f, 0xe8, 0x88, 0x6d, 0xaa,
+	0xa4, 0xde, 0x06, 0x92, 0x8f, 0xa1, 0x9a, 0x3a, 0x29, 0x92, 0xa9, 0xeb, 0x39, 0xec, 0x8d, 0x52,
+	0x4b, 0x12, 0xa2, 0x1a, 0x4d, 0x59, 0x14, 0xd9, 0x63, 0xa6, 0xc6, 0xe7, 0x98, 0xb4, 0x2c, 0xa8,
+	0xf5, 0x99, 0x67, 0x5f, 0x3a, 0xaf, 0xff, 0x45, 0x83, 0xaa, 0x14, 0xea, 0x71, 0x9b, 0x47, 0xa2,
+	0x71, 0xe0, 0x48, 0xc6, 0x83, 0x8e, 0xa4, 0xe2, 0x79, 0x3c, 0x8a, 0x13, 0x39, 0x12, 0xe9, 0x62,
+	0x26, 0x77, 0x73, 0x99, 0x62, 0x86, 0x09, 0x43, 0xf4, 0xc9, 0x31, 0xde, 0x22, 0x33, 0x1f, 0x7b,
+	0x0d, 0x41, 0x15, 0x49, 0x8f, 0xe2, 0x23, 0x47, 0x6a, 0x2e, 0x4f, 0x68, 0xa1, 0x8b, 0xf2, 0x68,
+	0x11, 0x77, 0x14, 0x65, 0x3d, 0x86, 0xcd, 0xae, 0x3f, 0x1e, 0x63, 0xd1, 0xf2, 0x46, 0xee, 0x18,
+	0x95, 0x63, 0xe7, 0x6c, 0xa2, 0x74, 0x96, 0x84, 0x38, 0xae, 0xe0, 0x25, 0xfd, 0x12, 0xa3, 0xe7,
+	0x63, 0x80, 0xd6, 0xcc, 0x71, 0xb9, 0x74, 0xca, 0x2d, 0x28, 0x4e, 0x19, 0x3f, 0xf5, 0x93, 0xc9,
+	0x4e, 0x52, 0x62, 0xc8, 0xad, 0xa2, 0x98, 0x9c, 0x17, 0xc8, 0xa7, 0x50, 0x54, 0x3d, 0xf2, 0x9a,
+	0xa1, 0x47, 0x6d, 0x8b, 0x2e, 0x49, 0x76, 0xe3, 0xfa, 0x6a, 0x39, 0xb9, 0x4b, 0x1e, 0xc3, 0xa6,
+	0x0c, 0xcc, 0xb8, 0xf5, 0xce, 0x5d, 0x11, 0xc7, 0x35, 0x29, 0xae, 0xfa, 0xee, 0x47, 0xa0, 0x68,
+	0xd5, 0xfa, 0xe7, 0xaf, 0x38, 0x5d, 0x95, 0xd2, 0xd8, 0xf7, 0x5b, 0xbf, 0xcb, 0x29, 0xdb, 0x28,
+	0x1b, 0xfa, 0xa1, 0x93, 0xc9, 0x5e, 0xda, 0x9a, 0xec, 0xa5, 0xbf, 0x65, 0xf6, 0xfa, 0x08, 0x2a,
+	0x41, 0xe8, 0x7a, 0x43, 0x37, 0xb0, 0x27, 0x68, 0x53, 0x85, 0xce, 0x19, 0x29, 0x78, 0xe5, 0x33,
+	0xf0, 0x9a, 0xbf, 0x42, 0x21, 0xfd, 0x0a, 0x02, 0xb2, 0x01, 0x63, 0x21, 0x02, 0xa0, 0x42, 0x71,
+	0x2d, 0xca, 0x99, 0x82, 0xc8, 0xfc, 0x7f, 0x97, 0x8a, 0xe2, 0x1c, 0xe2, 0x91, 0xa1, 0xef, 0x30,
+	0x35, 0x2b, 0xe1, 0x5a, 0x00, 0x04, 0xb1, 0x83, 0xfd, 0x5a, 0x85, 0x4a, 0x82, 0xec, 0x42, 0x49,
+	0x0e, 0x89, 0x91, 0x09, 0x4b, 0x21, 0x9b, 0x7a, 0x7b, 0x1a, 0x8b, 0x49, 0xb4, 0x06, 0x13, 0xfb,
+	0x82, 0x39, 0xd8, 0x3d, 0x95, 0x69, 0x42, 0x8b, 0x58, 0x48, 0xf2, 0xeb, 0xa9, 0x1d, 0x9d, 0x62,
+	0x63, 0x57, 0xa1, 0xb5, 0x98, 0xf9, 0xdc, 0x8e, 0x4e, 0x85, 0x72, 0xb8, 0x57, 0x93, 0xca, 0x89,
+	0xf5, 0xde, 0xdf, 0x0b, 0x50, 0xd9, 0x67, 0x2c, 0x94, 0xd1, 0xb2, 0x07, 0xa5, 0x67, 0x8c, 0x8b,
+	0x35, 0xb9, 0xb9, 0x00, 0x1d, 0x04, 0x6c, 0x63, 0x11, 0x50, 0xd6, 0x86, 0x98, 0x8c, 0xba, 0x6e,
+	0xc4, 0xe5, 0x05, 0xff, 0xb7, 0x72, 0xc8, 0x5a, 0x71, 0x6c, 0x57, 0x23, 0x6d, 0xd8, 0x52, 0x1f,
+	0x4b, 0x9a, 0xfc, 0x35, 0xc7, 0xd7, 0xcd, 0xee, 0xd6, 0x06, 0xf9, 0x46, 0xce, 0x36, 0xf2, 0xeb,
+	0x37, 0x57, 0x4d, 0x2d, 0x97, 0x9c, 0xde, 0xd5, 0xc8, 0x0b, 0xb8, 0x11, 0x2b, 0x31, 0x6f, 0x2e,
+	0x3e, 0x58, 0xdb, 0x18, 0x34, 0x16, 0x8b, 0xf1, 0xfc, 0x94, 0xb5, 0x41, 0x76, 0x01, 0xda, 0x38,
+	0xa0, 0xa2, 0x07, 0x17, 0x8d, 0x5e, 0xe5, 0xbc, 0x2f, 0x01, 0xe4, 0xa4, 0x7f, 0x5d, 0x9f, 0xd7,
+	0x5e, 0x79, 0xce, 0x3b, 0x1c, 0x7c, 0x0c, 0x30, 0xff, 0xf3, 0x8c, 0xa4, 0xa7, 0xbc, 0xa5, 0xff,
+	0xd4, 0x56, 0x1d, 0x7f, 0x01, 0x30, 0xff, 0x43, 0x26, 0x73, 0x7c, 0xe9, 0x0f, 0xa4, 0xc6, 0xed,
+	0x35, 0xbb, 0xf2, 0x5f, 0x1c, 0xa9, 0xcb, 0x77, 0x36, 0x1f, 0x9e, 0x5e, 0x8a, 0x9c, 0x9b, 0xab,
+	0x9a, 0x1f, 0xf1, 0x72, 0x7b, 0xff, 0xcd, 0x41, 0x3d, 0x41, 0x6e, 0xcb, 0x99, 0xba, 0x1e, 0x69,
+	0xc7, 0x25, 0x57, 0x5e, 0x69, 0xae, 0x2b, 0xe9, 0x8d, 0x5b, 0x4b, 0x3b, 0x58, 0xa4, 0x11, 0x11,
+	0xbf, 0x8a, 0xeb, 0x9e, 0xbc, 0x64, 0xe9, 0x15, 0xcd, 0xa5, 0xd2, 0x9a, 0xe0, 0x69, 0x47, 0x23,
+	0xcf, 0xc1, 0xe8, 0x31, 0x9e, 0xfd, 0x73, 0x7c, 0x6d, 0x62, 0x6c, 0xac, 0xdd, 0xb1, 0x36, 0x08,
+	0x05, 0x22, 0xe2, 0x2a, 0xc3, 0x8e, 0xc8, 0xff, 0xaf, 0x3b, 0xa1, 0xfc, 0x75, 0xc9, 0x8d, 0xbb,
+	0x1a, 0xd9, 0x17, 0x7f, 0x46, 0x44, 0x5c, 0x15, 0x66, 0x92, 0x8e, 0x8c, 0x74, 0xb1, 0x6e, 0xdc,
+	0x5a, 0xda, 0xc0, 0x02, 0xad, 0xee, 0x80, 0x1e, 0xe3, 0xaa, 0x06, 0x66, 0x6c, 0xcb, 0xd4, 0xc5,
+	0xc6, 0xda, 0x1d, 0x6b, 0x83, 0x3c, 0x51, 0x4f, 0x8f, 0x79, 0x2e, 0xf3, 0xf4, 0xf3, 0xe2, 0xd8,
+	0x58, 0x4a, 0x88, 0xb2, 0x60, 0x08, 0x25, 0xf6, 0xef, 0xc3, 0x87, 0xc1, 0x69, 0xe8, 0x46, 0x43,
+	0x7b, 0xcc, 0x50, 0xcc, 0x0e, 0x82, 0xb9, 0xf8, 0xfe, 0x1c, 0x18, 0xc7, 0xa2, 0x54, 0x1c, 0x6b,
+	0x27, 0x45, 0xac, 0x19, 0xbf, 0xf8, 0xdf, 0x00, 0xf1, 0x09, 0x07, 0x30, 0x49, 0x19, 0x00, 0x00,
 }
@@ -13,16 +13,213 @@ from google.protobuf import descriptor_pb2
 _sym_db = _symbol_database.Default()
 
 
+from google.protobuf import field_mask_pb2 as google_dot_protobuf_dot_field__mask__pb2
+from google.protobuf import timestamp_pb2 as google_dot_protobuf_dot_timestamp__pb2
 
 
 DESCRIPTOR = _descriptor.FileDescriptor(
   name='beer_likes.proto',
   package='beerlikes',
   syntax='proto3',
-  serialized_pb=_b('\n\x10\x62\x65\x65r_likes.proto\x12\tbeerlikes\"#\n\x07RefType\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\n\n\x02id\x18\x02 \x01(\t\"G\n\x04Like\x12$\n\x08ref_type\x18\x01 \x01(\x0b\x32\x12.beerlikes.RefType\x12\n\n\x02id\x18\x02 \x01(\t\x12\r\n\x05liked\x18\x03 \x01(\x08\"\x17\n\tLikeQuery\x12\n\n\x02id\x18\x01 \x01(\t\"2\n\nLikesQuery\x12$\n\x08ref_type\x18\x01 \x01(\x0b\x32\x12.beerlikes.RefType\"S\n\x0cLikesSummary\x12\x1e\n\x05likes\x18\x01 \x03(\x0b\x32\x0f.beerlikes.Like\x12\r\n\x05total\x18\x02 \x01(\x05\x12\x14\n\x0c\x65lapsed_time\x18\x03 \x01(\x04\x32\xbd\x01\n\tBeerLikes\x12\x32\n\x07GetLike\x12\x14.beerlikes.LikeQuery\x1a\x0f.beerlikes.Like\"\x00\x12\x37\n\tListLikes\x12\x15.beerlikes.LikesQuery\x1a\x0f.beerlikes.Like\"\x00\x30\x01\x12\x43\n\x0fGetLikesSummary\x12\x15.beerlikes.LikesQuery\x1a\x17.beerlikes.LikesSummary\"\x00\x42/\n\x1bphriscage.beerapp.beerlikesB\x0e\x42\x65\x65rLikesProtoP\x01\x62\x06proto3')
+  serialized_pb=_b('\n\x10\x62\x65\x65r_likes.proto\x12\tbeerlikes\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"#\n\x07RefType\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\n\n\x02id\x18\x02 \x01(\t\"Y\n\rRefTypeParent\x12$\n\x08ref_type\x18\x01 \x01(\x0b\x32\x12.beerlikes.RefType\x12\"\n\x06parent\x18\x02 \x01(\x0b\x32\x12.beerlikes.RefType\"#\n\x13RefTypeParentsQuery\x12\x0c\n\x04name\x18\x01 \x01(\t\"\xea\x01\n\x04Like\x12$\n\x08ref_type\x18\x01 \x01(\x0b\x32\x12.beerlikes.RefType\x12\n\n\x02id\x18\x02 \x01(\t\x12\r\n\x05liked\x18\x03 \x01(\x08\x12.\n\ncreated_at\x18\x04 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x0f\n\x07version\x18\x05 \x01(\x04\x12.\n\ndeleted_at\x18\x06 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x0f\n\x07user_id\x18\x07 \x01(\t\x12\x10\n\x08reaction\x18\x08 \x01(\t\x12\r\n\x05value\x18\t \x01(\x05\"y\n\tLikeQuery\x12\n\n\x02id\x18\x01 \x01(\t\x12\x18\n\x10\x65xpected_version\x18\x02 \x01(\x04\x12\x17\n\x0finclude_deleted\x18\x03 \x01(\x08\x12-\n\tread_mask\x18\x04 \x01(\x0b\x32\x1a.google.protobuf.FieldMask\"\xac\x01\n\x11UpdateLikeRequest\x12\x1d\n\x04like\x18\x01 \x01(\x0b\x32\x0f.beerlikes.Like\x12\x18\n\x10\x65xpected_version\x18\x02 \x01(\x04\x12/\n\x0bupdate_mask\x18\x03 \x01(\x0b\x32\x1a.google.protobuf.FieldMask\x12-\n\tread_mask\x18\x04 \x01(\x0b\x32\x1a.google.protobuf.FieldMask\"\xea\x01\n\x11ToggleLikeRequest\x12\x0f\n\x07user_id\x18\x01 \x01(\t\x12$\n\x08ref_type\x18\x02 \x01(\x0b\x32\x12.beerlikes.RefType\x12\x33\n\x06target\x18\x03 \x01(\x0e\x32#.beerlikes.ToggleLikeRequest.Target\x12-\n\tread_mask\x18\x04 \x01(\x0b\x32\x1a.google.protobuf.FieldMask\":\n\x06Target\x12\n\n\x06TOGGLE\x10\x00\x12\t\n\x05LIKED\x10\x01\x12\x0c\n\x08\x44ISLIKED\x10\x02\x12\x0b\n\x07\x43LEARED\x10\x03\"]\n\x12ToggleLikeResponse\x12\x1d\n\x04like\x18\x01 \x01(\x0b\x32\x0f.beerlikes.Like\x12(\n\x07summary\x18\x02 \x01(\x0b\x32\x17.beerlikes.LikesSummary\"\xc4\x04\n\nLikesQuery\x12$\n\x08ref_type\x18\x01 \x01(\x0b\x32\x12.beerlikes.RefType\x12\x1a\n\x12if_version_changed\x18\x02 \x01(\x04\x12\x17\n\x0finclude_deleted\x18\x03 \x01(\x08\x12\x0e\n\x06rollup\x18\x04 \x01(\x08\x12\x11\n\tid_prefix\x18\x05 \x01(\t\x12\x0b\n\x03ids\x18\x06 \x03(\t\x12\x30\n\x05liked\x18\x07 \x01(\x0e\x32!.beerlikes.LikesQuery.LikedFilter\x12\x0f\n\x07user_id\x18\x08 \x01(\t\x12\x31\n\rcreated_after\x18\t \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x32\n\x0e\x63reated_before\x18\n \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x10\n\x08like_ids\x18\x0b \x03(\t\x12/\n\x08order_by\x18\x0c \x01(\x0e\x32\x1d.beerlikes.LikesQuery.OrderBy\x12\r\n\x05limit\x18\r \x01(\x05\x12-\n\tread_mask\x18\x0e \x01(\x0b\x32\x1a.google.protobuf.FieldMask\"9\n\x0bLikedFilter\x12\x07\n\x03\x41LL\x10\x00\x12\x0e\n\nLIKED_ONLY\x10\x01\x12\x11\n\rDISLIKED_ONLY\x10\x02\"E\n\x07OrderBy\x12\t\n\x05SAVED\x10\x00\x12\x12\n\x0e\x43REATED_AT_ASC\x10\x01\x12\x13\n\x0f\x43REATED_AT_DESC\x10\x02\x12\x06\n\x02ID\x10\x03\"\x94\x01\n\tRankQuery\x12\x0c\n\x04name\x18\x01 \x01(\t\x12.\n\x08order_by\x18\x02 \x01(\x0e\x32\x1c.beerlikes.RankQuery.OrderBy\x12\r\n\x05limit\x18\x03 \x01(\x05\x12\x0e\n\x06rollup\x18\x04 \x01(\x08\"*\n\x07OrderBy\x12\t\n\x05TOTAL\x10\x00\x12\t\n\x05SCORE\x10\x01\x12\t\n\x05RATIO\x10\x02\"\xb0\x03\n\x0cLikesSummary\x12\x1e\n\x05likes\x18\x01 \x03(\x0b\x32\x0f.beerlikes.Like\x12\r\n\x05total\x18\x02 \x01(\x05\x12\x14\n\x0c\x65lapsed_time\x18\x03 \x01(\x04\x12$\n\x08ref_type\x18\x04 \x01(\x0b\x32\x12.beerlikes.RefType\x12\x12\n\nlike_count\x18\x05 \x01(\x05\x12\x15\n\rdislike_count\x18\x06 \x01(\x05\x12\x12\n\nlike_ratio\x18\x07 \x01(\x01\x12\r\n\x05score\x18\x08 \x01(\x01\x12\x0f\n\x07version\x18\t \x01(\x04\x12\x14\n\x0cnot_modified\x18\n \x01(\x08\x12\x15\n\rdeleted_count\x18\x0b \x01(\x05\x12\x44\n\x0freaction_counts\x18\x0c \x03(\x0b\x32+.beerlikes.LikesSummary.ReactionCountsEntry\x12\x14\n\x0crating_count\x18\r \x01(\x05\x12\x16\n\x0e\x61verage_rating\x18\x0e \x01(\x01\x1a\x35\n\x13ReactionCountsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\x05:\x02\x38\x01\"\xf0\x01\n\x0eHistogramQuery\x12$\n\x08ref_type\x18\x01 \x01(\x0b\x32\x12.beerlikes.RefType\x12.\n\nstart_time\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12,\n\x08\x65nd_time\x18\x03 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x39\n\x0b\x62ucket_size\x18\x04 \x01(\x0e\x32$.beerlikes.HistogramQuery.BucketSize\"\x1f\n\nBucketSize\x12\x07\n\x03\x44\x41Y\x10\x00\x12\x08\n\x04WEEK\x10\x01\"l\n\x0fHistogramBucket\x12.\n\nstart_time\x18\x01 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x12\n\nlike_count\x18\x02 \x01(\x05\x12\x15\n\rdislike_count\x18\x03 \x01(\x05\"\xb4\x01\n\x0eLikesHistogram\x12$\n\x08ref_type\x18\x01 \x01(\x0b\x32\x12.beerlikes.RefType\x12\x39\n\x0b\x62ucket_size\x18\x02 \x01(\x0e\x32$.beerlikes.HistogramQuery.BucketSize\x12+\n\x07\x62uckets\x18\x03 \x03(\x0b\x32\x1a.beerlikes.HistogramBucket\x12\x14\n\x0c\x65lapsed_time\x18\x04 \x01(\x04\"\xa9\x02\n\tLikeEvent\x12\'\n\x04type\x18\x01 \x01(\x0e\x32\x19.beerlikes.LikeEvent.Type\x12\x10\n\x08sequence\x18\x02 \x01(\x04\x12(\n\x04time\x18\x03 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x1d\n\x04like\x18\x04 \x01(\x0b\x32\x0f.beerlikes.Like\x12(\n\x06parent\x18\x05 \x01(\x0b\x32\x18.beerlikes.RefTypeParent\x12-\n\x11previous_ref_type\x18\x06 \x01(\x0b\x32\x12.beerlikes.RefType\"?\n\x04Type\x12\x08\n\x04LIKE\x10\x00\x12\n\n\x06UNLIKE\x10\x01\x12\n\n\x06UPDATE\x10\x02\x12\t\n\x05PURGE\x10\x03\x12\n\n\x06PARENT\x10\x04\"h\n\rExportRequest\x12/\n\x06\x66ormat\x18\x01 \x01(\x0e\x32\x1f.beerlikes.ExportRequest.Format\"&\n\x06\x46ormat\x12\x08\n\x04JSON\x10\x00\x12\t\n\x05JSONL\x10\x01\x12\x07\n\x03\x43SV\x10\x02\"\x1b\n\x0b\x45xportChunk\x12\x0c\n\x04\x64\x61ta\x18\x01 \x01(\x0c\"q\n\rImportSummary\x12\x10\n\x08imported\x18\x01 \x01(\x05\x12\x10\n\x08rejected\x18\x02 \x01(\x05\x12&\n\x06\x65rrors\x18\x03 \x03(\x0b\x32\x16.beerlikes.ImportError\x12\x14\n\x0c\x65lapsed_time\x18\x04 \x01(\x04\"-\n\x0bImportError\x12\r\n\x05index\x18\x01 \x01(\x05\x12\x0f\n\x07message\x18\x02 \x01(\t\"\x1c\n\x0cTenantsQuery\x12\x0c\n\x04name\x18\x01 \x01(\t\"x\n\x0bTenantStats\x12\x0e\n\x06tenant\x18\x01 \x01(\t\x12\r\n\x05likes\x18\x02 \x01(\x05\x12\x15\n\rdeleted_likes\x18\x03 \x01(\x05\x12\x11\n\tref_types\x18\x04 \x01(\x05\x12\x10\n\x08requests\x18\x05 \x01(\x04\x12\x0e\n\x06\x65rrors\x18\x06 \x01(\x04\".\n\rLoggingConfig\x12\r\n\x05level\x18\x01 \x01(\t\x12\x0e\n\x06\x66ormat\x18\x02 \x01(\t\"\x1c\n\nAuditQuery\x12\x0e\n\x06method\x18\x01 \x01(\t\"\xaf\x01\n\x0b\x41uditChange\x12\x1f\n\x06\x62\x65\x66ore\x18\x01 \x01(\x0b\x32\x0f.beerlikes.Like\x12\x1e\n\x05\x61\x66ter\x18\x02 \x01(\x0b\x32\x0f.beerlikes.Like\x12/\n\rparent_before\x18\x03 \x01(\x0b\x32\x18.beerlikes.RefTypeParent\x12.\n\x0cparent_after\x18\x04 \x01(\x0b\x32\x18.beerlikes.RefTypeParent\"\x9b\x02\n\x0b\x41uditRecord\x12\x10\n\x08sequence\x18\x01 \x01(\x04\x12(\n\x04time\x18\x02 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12\x11\n\tprincipal\x18\x03 \x01(\t\x12\x0e\n\x06tenant\x18\x04 \x01(\t\x12\x0e\n\x06method\x18\x05 \x01(\t\x12\x0c\n\x04peer\x18\x06 \x01(\t\x12\x12\n\nrequest_id\x18\x07 \x01(\t\x12\x0c\n\x04\x63ode\x18\x08 \x01(\t\x12\r\n\x05\x65rror\x18\t \x01(\t\x12\'\n\x07\x63hanges\x18\n \x03(\x0b\x32\x16.beerlikes.AuditChange\x12\x10\n\x08replayed\x18\r \x01(\x08\x12\x15\n\rprevious_hash\x18\x0b \x01(\t\x12\x0c\n\x04hash\x18\x0c \x01(\t2\xb7\x05\n\tBeerLikes\x12\x32\n\x07GetLike\x12\x14.beerlikes.LikeQuery\x1a\x0f.beerlikes.Like\"\x00\x12\x37\n\tListLikes\x12\x15.beerlikes.LikesQuery\x1a\x0f.beerlikes.Like\"\x00\x30\x01\x12\x43\n\x0fGetLikesSummary\x12\x15.beerlikes.LikesQuery\x1a\x17.beerlikes.LikesSummary\"\x00\x12>\n\tRankLikes\x12\x14.beerlikes.RankQuery\x1a\x17.beerlikes.LikesSummary\"\x00\x30\x01\x12K\n\x11GetLikesHistogram\x12\x19.beerlikes.HistogramQuery\x1a\x19.beerlikes.LikesHistogram\"\x00\x12\x30\n\nCreateLike\x12\x0f.beerlikes.Like\x1a\x0f.beerlikes.Like\"\x00\x12\x35\n\nDeleteLike\x12\x14.beerlikes.LikeQuery\x1a\x0f.beerlikes.Like\"\x00\x12\x37\n\x0cUndeleteLike\x12\x14.beerlikes.LikeQuery\x1a\x0f.beerlikes.Like\"\x00\x12=\n\nUpdateLike\x12\x1c.beerlikes.UpdateLikeRequest\x1a\x0f.beerlikes.Like\"\x00\x12K\n\nToggleLike\x12\x1c.beerlikes.ToggleLikeRequest\x1a\x1d.beerlikes.ToggleLikeResponse\"\x00\x12=\n\nWatchLikes\x12\x15.beerlikes.LikesQuery\x1a\x14.beerlikes.LikeEvent\"\x00\x30\x01\x32\xfa\x03\n\x0e\x42\x65\x65rLikesAdmin\x12\x43\n\x0b\x45xportLikes\x12\x18.beerlikes.ExportRequest\x1a\x16.beerlikes.ExportChunk\"\x00\x30\x01\x12<\n\x0bImportLikes\x12\x0f.beerlikes.Like\x1a\x18.beerlikes.ImportSummary\"\x00(\x01\x12H\n\x10SetRefTypeParent\x12\x18.beerlikes.RefTypeParent\x1a\x18.beerlikes.RefTypeParent\"\x00\x12R\n\x12ListRefTypeParents\x12\x1e.beerlikes.RefTypeParentsQuery\x1a\x18.beerlikes.RefTypeParent\"\x00\x30\x01\x12\x42\n\x0bListTenants\x12\x17.beerlikes.TenantsQuery\x1a\x16.beerlikes.TenantStats\"\x00\x30\x01\x12\x42\n\nSetLogging\x12\x18.beerlikes.LoggingConfig\x1a\x18.beerlikes.LoggingConfig\"\x00\x12?\n\nWatchAudit\x12\x15.beerlikes.AuditQuery\x1a\x16.beerlikes.AuditRecord\"\x00\x30\x01\x42/\n\x1bphriscage.beerapp.beerlikesB\x0e\x42\x65\x65rLikesProtoP\x01\x62\x06proto3')
+  ,
+  dependencies=[google_dot_protobuf_dot_field__mask__pb2.DESCRIPTOR,google_dot_protobuf_dot_timestamp__pb2.DESCRIPTOR,])
+
+
+
+_TOGGLELIKEREQUEST_TARGET = _descriptor.EnumDescriptor(
+  name='Target',
+  full_name='beerlikes.ToggleLikeRequest.Target',
+  filename=None,
+  file=DESCRIPTOR,
+  values=[
+    _descriptor.EnumValueDescriptor(
+      name='TOGGLE', index=0, number=0,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='LIKED', index=1, number=1,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='DISLIKED', index=2, number=2,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='CLEARED', index=3, number=3,
+      options=None,
+      type=None),
+  ],
+  containing_type=None,
+  options=None,
+  serialized_start=975,
+  serialized_end=1033,
+)
+_sym_db.RegisterEnumDescriptor(_TOGGLELIKEREQUEST_TARGET)
+
+_LIKESQUERY_LIKEDFILTER = _descriptor.EnumDescriptor(
+  name='LikedFilter',
+  full_name='beerlikes.LikesQuery.LikedFilter',
+  filename=None,
+  file=DESCRIPTOR,
+  values=[
+    _descriptor.EnumValueDescriptor(
+      name='ALL', index=0, number=0,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='LIKED_ONLY', index=1, number=1,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='DISLIKED_ONLY', index=2, number=2,
+      options=None,
+      type=None),
+  ],
+  containing_type=None,
+  options=None,
+  serialized_start=1583,
+  serialized_end=1640,
+)
+_sym_db.RegisterEnumDescriptor(_LIKESQUERY_LIKEDFILTER)
+
+_LIKESQUERY_ORDERBY = _descriptor.EnumDescriptor(
+  name='OrderBy',
+  full_name='beerlikes.LikesQuery.OrderBy',
+  filename=None,
+  file=DESCRIPTOR,
+  values=[
+    _descriptor.EnumValueDescriptor(
+      name='SAVED', index=0, number=0,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='CREATED_AT_ASC', index=1, number=1,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='CREATED_AT_DESC', index=2, number=2,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='ID', index=3, number=3,
+      options=None,
+      type=None),
+  ],
+  containing_type=None,
+  options=None,
+  serialized_start=1642,
+  serialized_end=1711,
+)
+_sym_db.RegisterEnumDescriptor(_LIKESQUERY_ORDERBY)
+
+_RANKQUERY_ORDERBY = _descriptor.EnumDescriptor(
+  name='OrderBy',
+  full_name='beerlikes.RankQuery.OrderBy',
+  filename=None,
+  file=DESCRIPTOR,
+  values=[
+    _descriptor.EnumValueDescriptor(
+      name='TOTAL', index=0, number=0,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='SCORE', index=1, number=1,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='RATIO', index=2, number=2,
+      options=None,
+      type=None),
+  ],
+  containing_type=None,
+  options=None,
+  serialized_start=1820,
+  serialized_end=1862,
+)
+_sym_db.RegisterEnumDescriptor(_RANKQUERY_ORDERBY)
+
+_HISTOGRAMQUERY_BUCKETSIZE = _descriptor.EnumDescriptor(
+  name='BucketSize',
+  full_name='beerlikes.HistogramQuery.BucketSize',
+  filename=None,
+  file=DESCRIPTOR,
+  values=[
+    _descriptor.EnumValueDescriptor(
+      name='DAY', index=0, number=0,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='WEEK', index=1, number=1,
+      options=None,
+      type=None),
+  ],
+  containing_type=None,
+  options=None,
+  serialized_start=2509,
+  serialized_end=2540,
 )
+_sym_db.RegisterEnumDescriptor(_HISTOGRAMQUERY_BUCKETSIZE)
 
+_LIKEEVENT_TYPE = _descriptor.EnumDescriptor(
+  name='Type',
+  full_name='beerlikes.LikeEvent.Type',
+  filename=None,
+  file=DESCRIPTOR,
+  values=[
+    _descriptor.EnumValueDescriptor(
+      name='LIKE', index=0, number=0,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='UNLIKE', index=1, number=1,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='UPDATE', index=2, number=2,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='PURGE', index=3, number=3,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='PARENT', index=4, number=4,
+      options=None,
+      type=None),
+  ],
+  containing_type=None,
+  options=None,
+  serialized_start=3070,
+  serialized_end=3133,
+)
+_sym_db.RegisterEnumDescriptor(_LIKEEVENT_TYPE)
 
+_EXPORTREQUEST_FORMAT = _descriptor.EnumDescriptor(
+  name='Format',
+  full_name='beerlikes.ExportRequest.Format',
+  filename=None,
+  file=DESCRIPTOR,
+  values=[
+    _descriptor.EnumValueDescriptor(
+      name='JSON', index=0, number=0,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='JSONL', index=1, number=1,
+      options=None,
+      type=None),
+    _descriptor.EnumValueDescriptor(
+      name='CSV', index=2, number=2,
+      options=None,
+      type=None),
+  ],
+  containing_type=None,
+  options=None,
+  serialized_start=3201,
+  serialized_end=3239,
+)
+_sym_db.RegisterEnumDescriptor(_EXPORTREQUEST_FORMAT)
 
 
 _REFTYPE = _descriptor.Descriptor(
@@ -33,16 +230,1170 @@ _REFTYPE = _descriptor.Descriptor(
   containing_type=None,
   fields=[
     _descriptor.FieldDescriptor(
-      name='name', full_name='beerlikes.RefType.name', index=0,
-      number=1, type=9, cpp_type=9, label=1,
-      has_default_value=False, default_value=_b("").decode('utf-8'),
+      name='name', full_name='beerlikes.RefType.name', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='id', full_name='beerlikes.RefType.id', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=98,
+  serialized_end=133,
+)
+
+
+_REFTYPEPARENT = _descriptor.Descriptor(
+  name='RefTypeParent',
+  full_name='beerlikes.RefTypeParent',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='ref_type', full_name='beerlikes.RefTypeParent.ref_type', index=0,
+      number=1, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='parent', full_name='beerlikes.RefTypeParent.parent', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=135,
+  serialized_end=224,
+)
+
+
+_REFTYPEPARENTSQUERY = _descriptor.Descriptor(
+  name='RefTypeParentsQuery',
+  full_name='beerlikes.RefTypeParentsQuery',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='name', full_name='beerlikes.RefTypeParentsQuery.name', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=226,
+  serialized_end=261,
+)
+
+
+_LIKE = _descriptor.Descriptor(
+  name='Like',
+  full_name='beerlikes.Like',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='ref_type', full_name='beerlikes.Like.ref_type', index=0,
+      number=1, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='id', full_name='beerlikes.Like.id', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='liked', full_name='beerlikes.Like.liked', index=2,
+      number=3, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='created_at', full_name='beerlikes.Like.created_at', index=3,
+      number=4, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='version', full_name='beerlikes.Like.version', index=4,
+      number=5, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='deleted_at', full_name='beerlikes.Like.deleted_at', index=5,
+      number=6, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='user_id', full_name='beerlikes.Like.user_id', index=6,
+      number=7, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='reaction', full_name='beerlikes.Like.reaction', index=7,
+      number=8, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='value', full_name='beerlikes.Like.value', index=8,
+      number=9, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=264,
+  serialized_end=498,
+)
+
+
+_LIKEQUERY = _descriptor.Descriptor(
+  name='LikeQuery',
+  full_name='beerlikes.LikeQuery',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='id', full_name='beerlikes.LikeQuery.id', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='expected_version', full_name='beerlikes.LikeQuery.expected_version', index=1,
+      number=2, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='include_deleted', full_name='beerlikes.LikeQuery.include_deleted', index=2,
+      number=3, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='read_mask', full_name='beerlikes.LikeQuery.read_mask', index=3,
+      number=4, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=500,
+  serialized_end=621,
+)
+
+
+_UPDATELIKEREQUEST = _descriptor.Descriptor(
+  name='UpdateLikeRequest',
+  full_name='beerlikes.UpdateLikeRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='like', full_name='beerlikes.UpdateLikeRequest.like', index=0,
+      number=1, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='expected_version', full_name='beerlikes.UpdateLikeRequest.expected_version', index=1,
+      number=2, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='update_mask', full_name='beerlikes.UpdateLikeRequest.update_mask', index=2,
+      number=3, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='read_mask', full_name='beerlikes.UpdateLikeRequest.read_mask', index=3,
+      number=4, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=624,
+  serialized_end=796,
+)
+
+
+_TOGGLELIKEREQUEST = _descriptor.Descriptor(
+  name='ToggleLikeRequest',
+  full_name='beerlikes.ToggleLikeRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='user_id', full_name='beerlikes.ToggleLikeRequest.user_id', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='ref_type', full_name='beerlikes.ToggleLikeRequest.ref_type', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='target', full_name='beerlikes.ToggleLikeRequest.target', index=2,
+      number=3, type=14, cpp_type=8, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='read_mask', full_name='beerlikes.ToggleLikeRequest.read_mask', index=3,
+      number=4, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+    _TOGGLELIKEREQUEST_TARGET,
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=799,
+  serialized_end=1033,
+)
+
+
+_TOGGLELIKERESPONSE = _descriptor.Descriptor(
+  name='ToggleLikeResponse',
+  full_name='beerlikes.ToggleLikeResponse',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='like', full_name='beerlikes.ToggleLikeResponse.like', index=0,
+      number=1, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='summary', full_name='beerlikes.ToggleLikeResponse.summary', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1035,
+  serialized_end=1128,
+)
+
+
+_LIKESQUERY = _descriptor.Descriptor(
+  name='LikesQuery',
+  full_name='beerlikes.LikesQuery',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='ref_type', full_name='beerlikes.LikesQuery.ref_type', index=0,
+      number=1, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='if_version_changed', full_name='beerlikes.LikesQuery.if_version_changed', index=1,
+      number=2, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='include_deleted', full_name='beerlikes.LikesQuery.include_deleted', index=2,
+      number=3, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='rollup', full_name='beerlikes.LikesQuery.rollup', index=3,
+      number=4, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='id_prefix', full_name='beerlikes.LikesQuery.id_prefix', index=4,
+      number=5, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='ids', full_name='beerlikes.LikesQuery.ids', index=5,
+      number=6, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='liked', full_name='beerlikes.LikesQuery.liked', index=6,
+      number=7, type=14, cpp_type=8, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='user_id', full_name='beerlikes.LikesQuery.user_id', index=7,
+      number=8, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='created_after', full_name='beerlikes.LikesQuery.created_after', index=8,
+      number=9, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='created_before', full_name='beerlikes.LikesQuery.created_before', index=9,
+      number=10, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='like_ids', full_name='beerlikes.LikesQuery.like_ids', index=10,
+      number=11, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='order_by', full_name='beerlikes.LikesQuery.order_by', index=11,
+      number=12, type=14, cpp_type=8, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='limit', full_name='beerlikes.LikesQuery.limit', index=12,
+      number=13, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='read_mask', full_name='beerlikes.LikesQuery.read_mask', index=13,
+      number=14, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+    _LIKESQUERY_LIKEDFILTER,
+    _LIKESQUERY_ORDERBY,
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1131,
+  serialized_end=1711,
+)
+
+
+_RANKQUERY = _descriptor.Descriptor(
+  name='RankQuery',
+  full_name='beerlikes.RankQuery',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='name', full_name='beerlikes.RankQuery.name', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='order_by', full_name='beerlikes.RankQuery.order_by', index=1,
+      number=2, type=14, cpp_type=8, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='limit', full_name='beerlikes.RankQuery.limit', index=2,
+      number=3, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='rollup', full_name='beerlikes.RankQuery.rollup', index=3,
+      number=4, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+    _RANKQUERY_ORDERBY,
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1714,
+  serialized_end=1862,
+)
+
+
+_LIKESSUMMARY_REACTIONCOUNTSENTRY = _descriptor.Descriptor(
+  name='ReactionCountsEntry',
+  full_name='beerlikes.LikesSummary.ReactionCountsEntry',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='key', full_name='beerlikes.LikesSummary.ReactionCountsEntry.key', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='value', full_name='beerlikes.LikesSummary.ReactionCountsEntry.value', index=1,
+      number=2, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=_descriptor._ParseOptions(descriptor_pb2.MessageOptions(), _b('8\001')),
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2244,
+  serialized_end=2297,
+)
+
+_LIKESSUMMARY = _descriptor.Descriptor(
+  name='LikesSummary',
+  full_name='beerlikes.LikesSummary',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='likes', full_name='beerlikes.LikesSummary.likes', index=0,
+      number=1, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='total', full_name='beerlikes.LikesSummary.total', index=1,
+      number=2, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='elapsed_time', full_name='beerlikes.LikesSummary.elapsed_time', index=2,
+      number=3, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='ref_type', full_name='beerlikes.LikesSummary.ref_type', index=3,
+      number=4, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='like_count', full_name='beerlikes.LikesSummary.like_count', index=4,
+      number=5, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='dislike_count', full_name='beerlikes.LikesSummary.dislike_count', index=5,
+      number=6, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='like_ratio', full_name='beerlikes.LikesSummary.like_ratio', index=6,
+      number=7, type=1, cpp_type=5, label=1,
+      has_default_value=False, default_value=float(0),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='score', full_name='beerlikes.LikesSummary.score', index=7,
+      number=8, type=1, cpp_type=5, label=1,
+      has_default_value=False, default_value=float(0),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='version', full_name='beerlikes.LikesSummary.version', index=8,
+      number=9, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='not_modified', full_name='beerlikes.LikesSummary.not_modified', index=9,
+      number=10, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='deleted_count', full_name='beerlikes.LikesSummary.deleted_count', index=10,
+      number=11, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='reaction_counts', full_name='beerlikes.LikesSummary.reaction_counts', index=11,
+      number=12, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='rating_count', full_name='beerlikes.LikesSummary.rating_count', index=12,
+      number=13, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='average_rating', full_name='beerlikes.LikesSummary.average_rating', index=13,
+      number=14, type=1, cpp_type=5, label=1,
+      has_default_value=False, default_value=float(0),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[_LIKESSUMMARY_REACTIONCOUNTSENTRY, ],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=1865,
+  serialized_end=2297,
+)
+
+
+_HISTOGRAMQUERY = _descriptor.Descriptor(
+  name='HistogramQuery',
+  full_name='beerlikes.HistogramQuery',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='ref_type', full_name='beerlikes.HistogramQuery.ref_type', index=0,
+      number=1, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='start_time', full_name='beerlikes.HistogramQuery.start_time', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='end_time', full_name='beerlikes.HistogramQuery.end_time', index=2,
+      number=3, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='bucket_size', full_name='beerlikes.HistogramQuery.bucket_size', index=3,
+      number=4, type=14, cpp_type=8, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+    _HISTOGRAMQUERY_BUCKETSIZE,
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2300,
+  serialized_end=2540,
+)
+
+
+_HISTOGRAMBUCKET = _descriptor.Descriptor(
+  name='HistogramBucket',
+  full_name='beerlikes.HistogramBucket',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='start_time', full_name='beerlikes.HistogramBucket.start_time', index=0,
+      number=1, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='like_count', full_name='beerlikes.HistogramBucket.like_count', index=1,
+      number=2, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='dislike_count', full_name='beerlikes.HistogramBucket.dislike_count', index=2,
+      number=3, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2542,
+  serialized_end=2650,
+)
+
+
+_LIKESHISTOGRAM = _descriptor.Descriptor(
+  name='LikesHistogram',
+  full_name='beerlikes.LikesHistogram',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='ref_type', full_name='beerlikes.LikesHistogram.ref_type', index=0,
+      number=1, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='bucket_size', full_name='beerlikes.LikesHistogram.bucket_size', index=1,
+      number=2, type=14, cpp_type=8, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='buckets', full_name='beerlikes.LikesHistogram.buckets', index=2,
+      number=3, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='elapsed_time', full_name='beerlikes.LikesHistogram.elapsed_time', index=3,
+      number=4, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2653,
+  serialized_end=2833,
+)
+
+
+_LIKEEVENT = _descriptor.Descriptor(
+  name='LikeEvent',
+  full_name='beerlikes.LikeEvent',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='type', full_name='beerlikes.LikeEvent.type', index=0,
+      number=1, type=14, cpp_type=8, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='sequence', full_name='beerlikes.LikeEvent.sequence', index=1,
+      number=2, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='time', full_name='beerlikes.LikeEvent.time', index=2,
+      number=3, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='like', full_name='beerlikes.LikeEvent.like', index=3,
+      number=4, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='parent', full_name='beerlikes.LikeEvent.parent', index=4,
+      number=5, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='previous_ref_type', full_name='beerlikes.LikeEvent.previous_ref_type', index=5,
+      number=6, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+    _LIKEEVENT_TYPE,
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2836,
+  serialized_end=3133,
+)
+
+
+_EXPORTREQUEST = _descriptor.Descriptor(
+  name='ExportRequest',
+  full_name='beerlikes.ExportRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='format', full_name='beerlikes.ExportRequest.format', index=0,
+      number=1, type=14, cpp_type=8, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+    _EXPORTREQUEST_FORMAT,
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3135,
+  serialized_end=3239,
+)
+
+
+_EXPORTCHUNK = _descriptor.Descriptor(
+  name='ExportChunk',
+  full_name='beerlikes.ExportChunk',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='data', full_name='beerlikes.ExportChunk.data', index=0,
+      number=1, type=12, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b(""),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3241,
+  serialized_end=3268,
+)
+
+
+_IMPORTSUMMARY = _descriptor.Descriptor(
+  name='ImportSummary',
+  full_name='beerlikes.ImportSummary',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='imported', full_name='beerlikes.ImportSummary.imported', index=0,
+      number=1, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='rejected', full_name='beerlikes.ImportSummary.rejected', index=1,
+      number=2, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='errors', full_name='beerlikes.ImportSummary.errors', index=2,
+      number=3, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='elapsed_time', full_name='beerlikes.ImportSummary.elapsed_time', index=3,
+      number=4, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3270,
+  serialized_end=3383,
+)
+
+
+_IMPORTERROR = _descriptor.Descriptor(
+  name='ImportError',
+  full_name='beerlikes.ImportError',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='index', full_name='beerlikes.ImportError.index', index=0,
+      number=1, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='message', full_name='beerlikes.ImportError.message', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3385,
+  serialized_end=3430,
+)
+
+
+_TENANTSQUERY = _descriptor.Descriptor(
+  name='TenantsQuery',
+  full_name='beerlikes.TenantsQuery',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='name', full_name='beerlikes.TenantsQuery.name', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3432,
+  serialized_end=3460,
+)
+
+
+_TENANTSTATS = _descriptor.Descriptor(
+  name='TenantStats',
+  full_name='beerlikes.TenantStats',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='tenant', full_name='beerlikes.TenantStats.tenant', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='likes', full_name='beerlikes.TenantStats.likes', index=1,
+      number=2, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='deleted_likes', full_name='beerlikes.TenantStats.deleted_likes', index=2,
+      number=3, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='ref_types', full_name='beerlikes.TenantStats.ref_types', index=3,
+      number=4, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       options=None, file=DESCRIPTOR),
     _descriptor.FieldDescriptor(
-      name='id', full_name='beerlikes.RefType.id', index=1,
-      number=2, type=9, cpp_type=9, label=1,
-      has_default_value=False, default_value=_b("").decode('utf-8'),
+      name='requests', full_name='beerlikes.TenantStats.requests', index=4,
+      number=5, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='errors', full_name='beerlikes.TenantStats.errors', index=5,
+      number=6, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       options=None, file=DESCRIPTOR),
@@ -58,39 +1409,32 @@ _REFTYPE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=31,
-  serialized_end=66,
+  serialized_start=3462,
+  serialized_end=3582,
 )
 
 
-_LIKE = _descriptor.Descriptor(
-  name='Like',
-  full_name='beerlikes.Like',
+_LOGGINGCONFIG = _descriptor.Descriptor(
+  name='LoggingConfig',
+  full_name='beerlikes.LoggingConfig',
   filename=None,
   file=DESCRIPTOR,
   containing_type=None,
   fields=[
     _descriptor.FieldDescriptor(
-      name='ref_type', full_name='beerlikes.Like.ref_type', index=0,
-      number=1, type=11, cpp_type=10, label=1,
-      has_default_value=False, default_value=None,
+      name='level', full_name='beerlikes.LoggingConfig.level', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       options=None, file=DESCRIPTOR),
     _descriptor.FieldDescriptor(
-      name='id', full_name='beerlikes.Like.id', index=1,
+      name='format', full_name='beerlikes.LoggingConfig.format', index=1,
       number=2, type=9, cpp_type=9, label=1,
       has_default_value=False, default_value=_b("").decode('utf-8'),
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       options=None, file=DESCRIPTOR),
-    _descriptor.FieldDescriptor(
-      name='liked', full_name='beerlikes.Like.liked', index=2,
-      number=3, type=8, cpp_type=7, label=1,
-      has_default_value=False, default_value=False,
-      message_type=None, enum_type=None, containing_type=None,
-      is_extension=False, extension_scope=None,
-      options=None, file=DESCRIPTOR),
   ],
   extensions=[
   ],
@@ -103,20 +1447,20 @@ _LIKE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=68,
-  serialized_end=139,
+  serialized_start=3584,
+  serialized_end=3630,
 )
 
 
-_LIKEQUERY = _descriptor.Descriptor(
-  name='LikeQuery',
-  full_name='beerlikes.LikeQuery',
+_AUDITQUERY = _descriptor.Descriptor(
+  name='AuditQuery',
+  full_name='beerlikes.AuditQuery',
   filename=None,
   file=DESCRIPTOR,
   containing_type=None,
   fields=[
     _descriptor.FieldDescriptor(
-      name='id', full_name='beerlikes.LikeQuery.id', index=0,
+      name='method', full_name='beerlikes.AuditQuery.method', index=0,
       number=1, type=9, cpp_type=9, label=1,
       has_default_value=False, default_value=_b("").decode('utf-8'),
       message_type=None, enum_type=None, containing_type=None,
@@ -134,25 +1478,46 @@ _LIKEQUERY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=141,
-  serialized_end=164,
+  serialized_start=3632,
+  serialized_end=3660,
 )
 
 
-_LIKESQUERY = _descriptor.Descriptor(
-  name='LikesQuery',
-  full_name='beerlikes.LikesQuery',
+_AUDITCHANGE = _descriptor.Descriptor(
+  name='AuditChange',
+  full_name='beerlikes.AuditChange',
   filename=None,
   file=DESCRIPTOR,
   containing_type=None,
   fields=[
     _descriptor.FieldDescriptor(
-      name='ref_type', full_name='beerlikes.LikesQuery.ref_type', index=0,
+      name='before', full_name='beerlikes.AuditChange.before', index=0,
       number=1, type=11, cpp_type=10, label=1,
       has_default_value=False, default_value=None,
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='after', full_name='beerlikes.AuditChange.after', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='parent_before', full_name='beerlikes.AuditChange.parent_before', index=2,
+      number=3, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='parent_after', full_name='beerlikes.AuditChange.parent_after', index=3,
+      number=4, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
   ],
   extensions=[
   ],
@@ -165,36 +1530,106 @@ _LIKESQUERY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=166,
-  serialized_end=216,
+  serialized_start=3663,
+  serialized_end=3838,
 )
 
 
-_LIKESSUMMARY = _descriptor.Descriptor(
-  name='LikesSummary',
-  full_name='beerlikes.LikesSummary',
+_AUDITRECORD = _descriptor.Descriptor(
+  name='AuditRecord',
+  full_name='beerlikes.AuditRecord',
   filename=None,
   file=DESCRIPTOR,
   containing_type=None,
   fields=[
     _descriptor.FieldDescriptor(
-      name='likes', full_name='beerlikes.LikesSummary.likes', index=0,
-      number=1, type=11, cpp_type=10, label=3,
+      name='sequence', full_name='beerlikes.AuditRecord.sequence', index=0,
+      number=1, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='time', full_name='beerlikes.AuditRecord.time', index=1,
+      number=2, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='principal', full_name='beerlikes.AuditRecord.principal', index=2,
+      number=3, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='tenant', full_name='beerlikes.AuditRecord.tenant', index=3,
+      number=4, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='method', full_name='beerlikes.AuditRecord.method', index=4,
+      number=5, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='peer', full_name='beerlikes.AuditRecord.peer', index=5,
+      number=6, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='request_id', full_name='beerlikes.AuditRecord.request_id', index=6,
+      number=7, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='code', full_name='beerlikes.AuditRecord.code', index=7,
+      number=8, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='error', full_name='beerlikes.AuditRecord.error', index=8,
+      number=9, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='changes', full_name='beerlikes.AuditRecord.changes', index=9,
+      number=10, type=11, cpp_type=10, label=3,
       has_default_value=False, default_value=[],
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       options=None, file=DESCRIPTOR),
     _descriptor.FieldDescriptor(
-      name='total', full_name='beerlikes.LikesSummary.total', index=1,
-      number=2, type=5, cpp_type=1, label=1,
-      has_default_value=False, default_value=0,
+      name='replayed', full_name='beerlikes.AuditRecord.replayed', index=10,
+      number=13, type=8, cpp_type=7, label=1,
+      has_default_value=False, default_value=False,
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       options=None, file=DESCRIPTOR),
     _descriptor.FieldDescriptor(
-      name='elapsed_time', full_name='beerlikes.LikesSummary.elapsed_time', index=2,
-      number=3, type=4, cpp_type=4, label=1,
-      has_default_value=False, default_value=0,
+      name='previous_hash', full_name='beerlikes.AuditRecord.previous_hash', index=11,
+      number=11, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      options=None, file=DESCRIPTOR),
+    _descriptor.FieldDescriptor(
+      name='hash', full_name='beerlikes.AuditRecord.hash', index=12,
+      number=12, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=_b("").decode('utf-8'),
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       options=None, file=DESCRIPTOR),
@@ -210,18 +1645,88 @@ _LIKESSUMMARY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=218,
-  serialized_end=301,
+  serialized_start=3841,
+  serialized_end=4124,
 )
 
+_REFTYPEPARENT.fields_by_name['ref_type'].message_type = _REFTYPE
+_REFTYPEPARENT.fields_by_name['parent'].message_type = _REFTYPE
 _LIKE.fields_by_name['ref_type'].message_type = _REFTYPE
+_LIKE.fields_by_name['created_at'].message_type = google_dot_protobuf_dot_timestamp__pb2._TIMESTAMP
+_LIKE.fields_by_name['deleted_at'].message_type = google_dot_protobuf_dot_timestamp__pb2._TIMESTAMP
+_LIKEQUERY.fields_by_name['read_mask'].message_type = google_dot_protobuf_dot_field__mask__pb2._FIELDMASK
+_UPDATELIKEREQUEST.fields_by_name['like'].message_type = _LIKE
+_UPDATELIKEREQUEST.fields_by_name['update_mask'].message_type = google_dot_protobuf_dot_field__mask__pb2._FIELDMASK
+_UPDATELIKEREQUEST.fields_by_name['read_mask'].message_type = google_dot_protobuf_dot_field__mask__pb2._FIELDMASK
+_TOGGLELIKEREQUEST.fields_by_name['ref_type'].message_type = _REFTYPE
+_TOGGLELIKEREQUEST.fields_by_name['target'].enum_type = _TOGGLELIKEREQUEST_TARGET
+_TOGGLELIKEREQUEST.fields_by_name['read_mask'].message_type = google_dot_protobuf_dot_field__mask__pb2._FIELDMASK
+_TOGGLELIKEREQUEST_TARGET.containing_type = _TOGGLELIKEREQUEST
+_TOGGLELIKERESPONSE.fields_by_name['like'].message_type = _LIKE
+_TOGGLELIKERESPONSE.fields_by_name['summary'].message_type = _LIKESSUMMARY
 _LIKESQUERY.fields_by_name['ref_type'].message_type = _REFTYPE
+_LIKESQUERY.fields_by_name['liked'].enum_type = _LIKESQUERY_LIKEDFILTER
+_LIKESQUERY.fields_by_name['created_after'].message_type = google_dot_protobuf_dot_timestamp__pb2._TIMESTAMP
+_LIKESQUERY.fields_by_name['created_before'].message_type = google_dot_protobuf_dot_timestamp__pb2._TIMESTAMP
+_LIKESQUERY.fields_by_name['order_by'].enum_type = _LIKESQUERY_ORDERBY
+_LIKESQUERY.fields_by_name['read_mask'].message_type = google_dot_protobuf_dot_field__mask__pb2._FIELDMASK
+_LIKESQUERY_LIKEDFILTER.containing_type = _LIKESQUERY
+_LIKESQUERY_ORDERBY.containing_type = _LIKESQUERY
+_RANKQUERY.fields_by_name['order_by'].enum_type = _RANKQUERY_ORDERBY
+_RANKQUERY_ORDERBY.containing_type = _RANKQUERY
+_LIKESSUMMARY_REACTIONCOUNTSENTRY.containing_type = _LIKESSUMMARY
 _LIKESSUMMARY.fields_by_name['likes'].message_type = _LIKE
+_LIKESSUMMARY.fields_by_name['ref_type'].message_type = _REFTYPE
+_LIKESSUMMARY.fields_by_name['reaction_counts'].message_type = _LIKESSUMMARY_REACTIONCOUNTSENTRY
+_HISTOGRAMQUERY.fields_by_name['ref_type'].message_type = _REFTYPE
+_HISTOGRAMQUERY.fields_by_name['start_time'].message_type = google_dot_protobuf_dot_timestamp__pb2._TIMESTAMP
+_HISTOGRAMQUERY.fields_by_name['end_time'].message_type = google_dot_protobuf_dot_timestamp__pb2._TIMESTAMP
+_HISTOGRAMQUERY.fields_by_name['bucket_size'].enum_type = _HISTOGRAMQUERY_BUCKETSIZE
+_HISTOGRAMQUERY_BUCKETSIZE.containing_type = _HISTOGRAMQUERY
+_HISTOGRAMBUCKET.fields_by_name['start_time'].message_type = google_dot_protobuf_dot_timestamp__pb2._TIMESTAMP
+_LIKESHISTOGRAM.fields_by_name['ref_type'].message_type = _REFTYPE
+_LIKESHISTOGRAM.fields_by_name['bucket_size'].enum_type = _HISTOGRAMQUERY_BUCKETSIZE
+_LIKESHISTOGRAM.fields_by_name['buckets'].message_type = _HISTOGRAMBUCKET
+_LIKEEVENT.fields_by_name['type'].enum_type = _LIKEEVENT_TYPE
+_LIKEEVENT.fields_by_name['time'].message_type = google_dot_protobuf_dot_timestamp__pb2._TIMESTAMP
+_LIKEEVENT.fields_by_name['like'].message_type = _LIKE
+_LIKEEVENT.fields_by_name['parent'].message_type = _REFTYPEPARENT
+_LIKEEVENT.fields_by_name['previous_ref_type'].message_type = _REFTYPE
+_LIKEEVENT_TYPE.containing_type = _LIKEEVENT
+_EXPORTREQUEST.fields_by_name['format'].enum_type = _EXPORTREQUEST_FORMAT
+_EXPORTREQUEST_FORMAT.containing_type = _EXPORTREQUEST
+_IMPORTSUMMARY.fields_by_name['errors'].message_type = _IMPORTERROR
+_AUDITCHANGE.fields_by_name['before'].message_type = _LIKE
+_AUDITCHANGE.fields_by_name['after'].message_type = _LIKE
+_AUDITCHANGE.fields_by_name['parent_before'].message_type = _REFTYPEPARENT
+_AUDITCHANGE.fields_by_name['parent_after'].message_type = _REFTYPEPARENT
+_AUDITRECORD.fields_by_name['time'].message_type = google_dot_protobuf_dot_timestamp__pb2._TIMESTAMP
+_AUDITRECORD.fields_by_name['changes'].message_type = _AUDITCHANGE
 DESCRIPTOR.message_types_by_name['RefType'] = _REFTYPE
+DESCRIPTOR.message_types_by_name['RefTypeParent'] = _REFTYPEPARENT
+DESCRIPTOR.message_types_by_name['RefTypeParentsQuery'] = _REFTYPEPARENTSQUERY
 DESCRIPTOR.message_types_by_name['Like'] = _LIKE
 DESCRIPTOR.message_types_by_name['LikeQuery'] = _LIKEQUERY
+DESCRIPTOR.message_types_by_name['UpdateLikeRequest'] = _UPDATELIKEREQUEST
+DESCRIPTOR.message_types_by_name['ToggleLikeRequest'] = _TOGGLELIKEREQUEST
+DESCRIPTOR.message_types_by_name['ToggleLikeResponse'] = _TOGGLELIKERESPONSE
 DESCRIPTOR.message_types_by_name['LikesQuery'] = _LIKESQUERY
+DESCRIPTOR.message_types_by_name['RankQuery'] = _RANKQUERY
 DESCRIPTOR.message_types_by_name['LikesSummary'] = _LIKESSUMMARY
+DESCRIPTOR.message_types_by_name['HistogramQuery'] = _HISTOGRAMQUERY
+DESCRIPTOR.message_types_by_name['HistogramBucket'] = _HISTOGRAMBUCKET
+DESCRIPTOR.message_types_by_name['LikesHistogram'] = _LIKESHISTOGRAM
+DESCRIPTOR.message_types_by_name['LikeEvent'] = _LIKEEVENT
+DESCRIPTOR.message_types_by_name['ExportRequest'] = _EXPORTREQUEST
+DESCRIPTOR.message_types_by_name['ExportChunk'] = _EXPORTCHUNK
+DESCRIPTOR.message_types_by_name['ImportSummary'] = _IMPORTSUMMARY
+DESCRIPTOR.message_types_by_name['ImportError'] = _IMPORTERROR
+DESCRIPTOR.message_types_by_name['TenantsQuery'] = _TENANTSQUERY
+DESCRIPTOR.message_types_by_name['TenantStats'] = _TENANTSTATS
+DESCRIPTOR.message_types_by_name['LoggingConfig'] = _LOGGINGCONFIG
+DESCRIPTOR.message_types_by_name['AuditQuery'] = _AUDITQUERY
+DESCRIPTOR.message_types_by_name['AuditChange'] = _AUDITCHANGE
+DESCRIPTOR.message_types_by_name['AuditRecord'] = _AUDITRECORD
 _sym_db.RegisterFileDescriptor(DESCRIPTOR)
 
 RefType = _reflection.GeneratedProtocolMessageType('RefType', (_message.Message,), dict(
@@ -231,6 +1736,20 @@ RefType = _reflection.GeneratedProtocolMessageType('RefType', (_message.Message,
   ))
 _sym_db.RegisterMessage(RefType)
 
+RefTypeParent = _reflection.GeneratedProtocolMessageType('RefTypeParent', (_message.Message,), dict(
+  DESCRIPTOR = _REFTYPEPARENT,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.RefTypeParent)
+  ))
+_sym_db.RegisterMessage(RefTypeParent)
+
+RefTypeParentsQuery = _reflection.GeneratedProtocolMessageType('RefTypeParentsQuery', (_message.Message,), dict(
+  DESCRIPTOR = _REFTYPEPARENTSQUERY,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.RefTypeParentsQuery)
+  ))
+_sym_db.RegisterMessage(RefTypeParentsQuery)
+
 Like = _reflection.GeneratedProtocolMessageType('Like', (_message.Message,), dict(
   DESCRIPTOR = _LIKE,
   __module__ = 'beer_likes_pb2'
@@ -245,6 +1764,27 @@ LikeQuery = _reflection.GeneratedProtocolMessageType('LikeQuery', (_message.Mess
   ))
 _sym_db.RegisterMessage(LikeQuery)
 
+UpdateLikeRequest = _reflection.GeneratedProtocolMessageType('UpdateLikeRequest', (_message.Message,), dict(
+  DESCRIPTOR = _UPDATELIKEREQUEST,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.UpdateLikeRequest)
+  ))
+_sym_db.RegisterMessage(UpdateLikeRequest)
+
+ToggleLikeRequest = _reflection.GeneratedProtocolMessageType('ToggleLikeRequest', (_message.Message,), dict(
+  DESCRIPTOR = _TOGGLELIKEREQUEST,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.ToggleLikeRequest)
+  ))
+_sym_db.RegisterMessage(ToggleLikeRequest)
+
+ToggleLikeResponse = _reflection.GeneratedProtocolMessageType('ToggleLikeResponse', (_message.Message,), dict(
+  DESCRIPTOR = _TOGGLELIKERESPONSE,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.ToggleLikeResponse)
+  ))
+_sym_db.RegisterMessage(ToggleLikeResponse)
+
 LikesQuery = _reflection.GeneratedProtocolMessageType('LikesQuery', (_message.Message,), dict(
   DESCRIPTOR = _LIKESQUERY,
   __module__ = 'beer_likes_pb2'
@@ -252,16 +1792,131 @@ LikesQuery = _reflection.GeneratedProtocolMessageType('LikesQuery', (_message.Me
   ))
 _sym_db.RegisterMessage(LikesQuery)
 
+RankQuery = _reflection.GeneratedProtocolMessageType('RankQuery', (_message.Message,), dict(
+  DESCRIPTOR = _RANKQUERY,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.RankQuery)
+  ))
+_sym_db.RegisterMessage(RankQuery)
+
 LikesSummary = _reflection.GeneratedProtocolMessageType('LikesSummary', (_message.Message,), dict(
+
+  ReactionCountsEntry = _reflection.GeneratedProtocolMessageType('ReactionCountsEntry', (_message.Message,), dict(
+    DESCRIPTOR = _LIKESSUMMARY_REACTIONCOUNTSENTRY,
+    __module__ = 'beer_likes_pb2'
+    # @@protoc_insertion_point(class_scope:beerlikes.LikesSummary.ReactionCountsEntry)
+    ))
+  ,
   DESCRIPTOR = _LIKESSUMMARY,
   __module__ = 'beer_likes_pb2'
   # @@protoc_insertion_point(class_scope:beerlikes.LikesSummary)
   ))
 _sym_db.RegisterMessage(LikesSummary)
+_sym_db.RegisterMessage(LikesSummary.ReactionCountsEntry)
+
+HistogramQuery = _reflection.GeneratedProtocolMessageType('HistogramQuery', (_message.Message,), dict(
+  DESCRIPTOR = _HISTOGRAMQUERY,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.HistogramQuery)
+  ))
+_sym_db.RegisterMessage(HistogramQuery)
+
+HistogramBucket = _reflection.GeneratedProtocolMessageType('HistogramBucket', (_message.Message,), dict(
+  DESCRIPTOR = _HISTOGRAMBUCKET,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.HistogramBucket)
+  ))
+_sym_db.RegisterMessage(HistogramBucket)
+
+LikesHistogram = _reflection.GeneratedProtocolMessageType('LikesHistogram', (_message.Message,), dict(
+  DESCRIPTOR = _LIKESHISTOGRAM,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.LikesHistogram)
+  ))
+_sym_db.RegisterMessage(LikesHistogram)
+
+LikeEvent = _reflection.GeneratedProtocolMessageType('LikeEvent', (_message.Message,), dict(
+  DESCRIPTOR = _LIKEEVENT,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.LikeEvent)
+  ))
+_sym_db.RegisterMessage(LikeEvent)
+
+ExportRequest = _reflection.GeneratedProtocolMessageType('ExportRequest', (_message.Message,), dict(
+  DESCRIPTOR = _EXPORTREQUEST,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.ExportRequest)
+  ))
+_sym_db.RegisterMessage(ExportRequest)
+
+ExportChunk = _reflection.GeneratedProtocolMessageType('ExportChunk', (_message.Message,), dict(
+  DESCRIPTOR = _EXPORTCHUNK,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.ExportChunk)
+  ))
+_sym_db.RegisterMessage(ExportChunk)
+
+ImportSummary = _reflection.GeneratedProtocolMessageType('ImportSummary', (_message.Message,), dict(
+  DESCRIPTOR = _IMPORTSUMMARY,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.ImportSummary)
+  ))
+_sym_db.RegisterMessage(ImportSummary)
+
+ImportError = _reflection.GeneratedProtocolMessageType('ImportError', (_message.Message,), dict(
+  DESCRIPTOR = _IMPORTERROR,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.ImportError)
+  ))
+_sym_db.RegisterMessage(ImportError)
+
+TenantsQuery = _reflection.GeneratedProtocolMessageType('TenantsQuery', (_message.Message,), dict(
+  DESCRIPTOR = _TENANTSQUERY,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.TenantsQuery)
+  ))
+_sym_db.RegisterMessage(TenantsQuery)
+
+TenantStats = _reflection.GeneratedProtocolMessageType('TenantStats', (_message.Message,), dict(
+  DESCRIPTOR = _TENANTSTATS,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.TenantStats)
+  ))
+_sym_db.RegisterMessage(TenantStats)
+
+LoggingConfig = _reflection.GeneratedProtocolMessageType('LoggingConfig', (_message.Message,), dict(
+  DESCRIPTOR = _LOGGINGCONFIG,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.LoggingConfig)
+  ))
+_sym_db.RegisterMessage(LoggingConfig)
+
+AuditQuery = _reflection.GeneratedProtocolMessageType('AuditQuery', (_message.Message,), dict(
+  DESCRIPTOR = _AUDITQUERY,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.AuditQuery)
+  ))
+_sym_db.RegisterMessage(AuditQuery)
+
+AuditChange = _reflection.GeneratedProtocolMessageType('AuditChange', (_message.Message,), dict(
+  DESCRIPTOR = _AUDITCHANGE,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.AuditChange)
+  ))
+_sym_db.RegisterMessage(AuditChange)
+
+AuditRecord = _reflection.GeneratedProtocolMessageType('AuditRecord', (_message.Message,), dict(
+  DESCRIPTOR = _AUDITRECORD,
+  __module__ = 'beer_likes_pb2'
+  # @@protoc_insertion_point(class_scope:beerlikes.AuditRecord)
+  ))
+_sym_db.RegisterMessage(AuditRecord)
 
 
 DESCRIPTOR.has_options = True
 DESCRIPTOR._options = _descriptor._ParseOptions(descriptor_pb2.FileOptions(), _b('\n\033phriscage.beerapp.beerlikesB\016BeerLikesProtoP\001'))
+_LIKESSUMMARY_REACTIONCOUNTSENTRY.has_options = True
+_LIKESSUMMARY_REACTIONCOUNTSENTRY._options = _descriptor._ParseOptions(descriptor_pb2.MessageOptions(), _b('8\001'))
 
 _BEERLIKES = _descriptor.ServiceDescriptor(
   name='BeerLikes',
@@ -269,8 +1924,8 @@ _BEERLIKES = _descriptor.ServiceDescriptor(
   file=DESCRIPTOR,
   index=0,
   options=None,
-  serialized_start=304,
-  serialized_end=493,
+  serialized_start=4127,
+  serialized_end=4822,
   methods=[
   _descriptor.MethodDescriptor(
     name='GetLike',
@@ -299,9 +1954,159 @@ _BEERLIKES = _descriptor.ServiceDescriptor(
     output_type=_LIKESSUMMARY,
     options=None,
   ),
+  _descriptor.MethodDescriptor(
+    name='RankLikes',
+    full_name='beerlikes.BeerLikes.RankLikes',
+    index=3,
+    containing_service=None,
+    input_type=_RANKQUERY,
+    output_type=_LIKESSUMMARY,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='GetLikesHistogram',
+    full_name='beerlikes.BeerLikes.GetLikesHistogram',
+    index=4,
+    containing_service=None,
+    input_type=_HISTOGRAMQUERY,
+    output_type=_LIKESHISTOGRAM,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='CreateLike',
+    full_name='beerlikes.BeerLikes.CreateLike',
+    index=5,
+    containing_service=None,
+    input_type=_LIKE,
+    output_type=_LIKE,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='DeleteLike',
+    full_name='beerlikes.BeerLikes.DeleteLike',
+    index=6,
+    containing_service=None,
+    input_type=_LIKEQUERY,
+    output_type=_LIKE,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='UndeleteLike',
+    full_name='beerlikes.BeerLikes.UndeleteLike',
+    index=7,
+    containing_service=None,
+    input_type=_LIKEQUERY,
+    output_type=_LIKE,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='UpdateLike',
+    full_name='beerlikes.BeerLikes.UpdateLike',
+    index=8,
+    containing_service=None,
+    input_type=_UPDATELIKEREQUEST,
+    output_type=_LIKE,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='ToggleLike',
+    full_name='beerlikes.BeerLikes.ToggleLike',
+    index=9,
+    containing_service=None,
+    input_type=_TOGGLELIKEREQUEST,
+    output_type=_TOGGLELIKERESPONSE,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='WatchLikes',
+    full_name='beerlikes.BeerLikes.WatchLikes',
+    index=10,
+    containing_service=None,
+    input_type=_LIKESQUERY,
+    output_type=_LIKEEVENT,
+    options=None,
+  ),
 ])
 _sym_db.RegisterServiceDescriptor(_BEERLIKES)
 
 DESCRIPTOR.services_by_name['BeerLikes'] = _BEERLIKES
 
+
+_BEERLIKESADMIN = _descriptor.ServiceDescriptor(
+  name='BeerLikesAdmin',
+  full_name='beerlikes.BeerLikesAdmin',
+  file=DESCRIPTOR,
+  index=1,
+  options=None,
+  serialized_start=4825,
+  serialized_end=5331,
+  methods=[
+  _descriptor.MethodDescriptor(
+    name='ExportLikes',
+    full_name='beerlikes.BeerLikesAdmin.ExportLikes',
+    index=0,
+    containing_service=None,
+    input_type=_EXPORTREQUEST,
+    output_type=_EXPORTCHUNK,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='ImportLikes',
+    full_name='beerlikes.BeerLikesAdmin.ImportLikes',
+    index=1,
+    containing_service=None,
+    input_type=_LIKE,
+    output_type=_IMPORTSUMMARY,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='SetRefTypeParent',
+    full_name='beerlikes.BeerLikesAdmin.SetRefTypeParent',
+    index=2,
+    containing_service=None,
+    input_type=_REFTYPEPARENT,
+    output_type=_REFTYPEPARENT,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='ListRefTypeParents',
+    full_name='beerlikes.BeerLikesAdmin.ListRefTypeParents',
+    index=3,
+    containing_service=None,
+    input_type=_REFTYPEPARENTSQUERY,
+    output_type=_REFTYPEPARENT,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='ListTenants',
+    full_name='beerlikes.BeerLikesAdmin.ListTenants',
+    index=4,
+    containing_service=None,
+    input_type=_TENANTSQUERY,
+    output_type=_TENANTSTATS,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='SetLogging',
+    full_name='beerlikes.BeerLikesAdmin.SetLogging',
+    index=5,
+    containing_service=None,
+    input_type=_LOGGINGCONFIG,
+    output_type=_LOGGINGCONFIG,
+    options=None,
+  ),
+  _descriptor.MethodDescriptor(
+    name='WatchAudit',
+    full_name='beerlikes.BeerLikesAdmin.WatchAudit',
+    index=6,
+    containing_service=None,
+    input_type=_AUDITQUERY,
+    output_type=_AUDITRECORD,
+    options=None,
+  ),
+])
+_sym_db.RegisterServiceDescriptor(_BEERLIKESADMIN)
+
+DESCRIPTOR.services_by_name['BeerLikesAdmin'] = _BEERLIKESADMIN
+
 # @@protoc_insertion_point(module_scope)
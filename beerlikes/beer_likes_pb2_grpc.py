@@ -5,9 +5,7 @@ import beer_likes_pb2 as beer__likes__pb2
 
 
 class BeerLikesStub(object):
-  """import "google/protobuf/timestamp.proto";
-
-  Interface exported by the server.
+  """Interface exported by the server.
   """
 
   def __init__(self, channel):
@@ -31,12 +29,50 @@ class BeerLikesStub(object):
         request_serializer=beer__likes__pb2.LikesQuery.SerializeToString,
         response_deserializer=beer__likes__pb2.LikesSummary.FromString,
         )
+    self.RankLikes = channel.unary_stream(
+        '/beerlikes.BeerLikes/RankLikes',
+        request_serializer=beer__likes__pb2.RankQuery.SerializeToString,
+        response_deserializer=beer__likes__pb2.LikesSummary.FromString,
+        )
+    self.GetLikesHistogram = channel.unary_unary(
+        '/beerlikes.BeerLikes/GetLikesHistogram',
+        request_serializer=beer__likes__pb2.HistogramQuery.SerializeToString,
+        response_deserializer=beer__likes__pb2.LikesHistogram.FromString,
+        )
+    self.CreateLike = channel.unary_unary(
+        '/beerlikes.BeerLikes/CreateLike',
+        request_serializer=beer__likes__pb2.Like.SerializeToString,
+        response_deserializer=beer__likes__pb2.Like.FromString,
+        )
+    self.DeleteLike = channel.unary_unary(
+        '/beerlikes.BeerLikes/DeleteLike',
+        request_serializer=beer__likes__pb2.LikeQuery.SerializeToString,
+        response_deserializer=beer__likes__pb2.Like.FromString,
+        )
+    self.UndeleteLike = channel.unary_unary(
+        '/beerlikes.BeerLikes/UndeleteLike',
+        request_serializer=beer__likes__pb2.LikeQuery.SerializeToString,
+        response_deserializer=beer__likes__pb2.Like.FromString,
+        )
+    self.UpdateLike = channel.unary_unary(
+        '/beerlikes.BeerLikes/UpdateLike',
+        request_serializer=beer__likes__pb2.UpdateLikeRequest.SerializeToString,
+        response_deserializer=beer__likes__pb2.Like.FromString,
+        )
+    self.ToggleLike = channel.unary_unary(
+        '/beerlikes.BeerLikes/ToggleLike',
+        request_serializer=beer__likes__pb2.ToggleLikeRequest.SerializeToString,
+        response_deserializer=beer__likes__pb2.ToggleLikeResponse.FromString,
+        )
+    self.WatchLikes = channel.unary_stream(
+        '/beerlikes.BeerLikes/WatchLikes',
+        request_serializer=beer__likes__pb2.LikesQuery.SerializeToString,
+        response_deserializer=beer__likes__pb2.LikeEvent.FromString,
+        )
 
 
 class BeerLikesServicer(object):
-  """import "google/protobuf/timestamp.proto";
-
-  Interface exported by the server.
+  """Interface exported by the server.
   """
 
   def GetLike(self, request, context):
@@ -45,7 +81,7 @@ class BeerLikesServicer(object):
     Obtains the like at a given RefType.
 
     A like with an empty id is returned if there's no like at the given
-    reftype.
+    reftype. Deleted likes are only returned with include_deleted.
     """
     context.set_code(grpc.StatusCode.UNIMPLEMENTED)
     context.set_details('Method not implemented!')
@@ -53,7 +89,8 @@ class BeerLikesServicer(object):
 
   def ListLikes(self, request, context):
     """Stream all the Likes at a given RefType
-    position.
+    position. Without a ref_type id, stream the likes of every RefType with
+    the name, or of the ids with the id_prefix, or of the ids.
     """
     context.set_code(grpc.StatusCode.UNIMPLEMENTED)
     context.set_details('Method not implemented!')
@@ -66,6 +103,68 @@ class BeerLikesServicer(object):
     context.set_details('Method not implemented!')
     raise NotImplementedError('Method not implemented!')
 
+  def RankLikes(self, request, context):
+    """Stream a LikesSummary for each RefType with the given name, ranked by
+    the requested order. The summaries do not include the individual likes.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def GetLikesHistogram(self, request, context):
+    """Fetch the like and dislike counts of a RefType per time bucket.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def CreateLike(self, request, context):
+    """Create a like or dislike for a RefType. The server assigns the id if it
+    is empty and the created_at if it is not set.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def DeleteLike(self, request, context):
+    """Delete the like with the given id and return it. The like is kept with
+    its deleted_at set until the server purges it.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def UndeleteLike(self, request, context):
+    """Restore a deleted like that has not been purged yet and return it.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def UpdateLike(self, request, context):
+    """Update a like and return it with its new version.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def ToggleLike(self, request, context):
+    """Atomically create, flip or delete the like of a user for a RefType to
+    reach the target state, and return it with the new counts of the RefType.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def WatchLikes(self, request, context):
+    """Stream the like events of a RefType, or of every RefType when it is not
+    set, as they happen. A watcher that falls too far behind is ended with
+    RESOURCE_EXHAUSTED.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
 
 def add_BeerLikesServicer_to_server(servicer, server):
   rpc_method_handlers = {
@@ -84,7 +183,202 @@ def add_BeerLikesServicer_to_server(servicer, server):
           request_deserializer=beer__likes__pb2.LikesQuery.FromString,
           response_serializer=beer__likes__pb2.LikesSummary.SerializeToString,
       ),
+      'RankLikes': grpc.unary_stream_rpc_method_handler(
+          servicer.RankLikes,
+          request_deserializer=beer__likes__pb2.RankQuery.FromString,
+          response_serializer=beer__likes__pb2.LikesSummary.SerializeToString,
+      ),
+      'GetLikesHistogram': grpc.unary_unary_rpc_method_handler(
+          servicer.GetLikesHistogram,
+          request_deserializer=beer__likes__pb2.HistogramQuery.FromString,
+          response_serializer=beer__likes__pb2.LikesHistogram.SerializeToString,
+      ),
+      'CreateLike': grpc.unary_unary_rpc_method_handler(
+          servicer.CreateLike,
+          request_deserializer=beer__likes__pb2.Like.FromString,
+          response_serializer=beer__likes__pb2.Like.SerializeToString,
+      ),
+      'DeleteLike': grpc.unary_unary_rpc_method_handler(
+          servicer.DeleteLike,
+          request_deserializer=beer__likes__pb2.LikeQuery.FromString,
+          response_serializer=beer__likes__pb2.Like.SerializeToString,
+      ),
+      'UndeleteLike': grpc.unary_unary_rpc_method_handler(
+          servicer.UndeleteLike,
+          request_deserializer=beer__likes__pb2.LikeQuery.FromString,
+          response_serializer=beer__likes__pb2.Like.SerializeToString,
+      ),
+      'UpdateLike': grpc.unary_unary_rpc_method_handler(
+          servicer.UpdateLike,
+          request_deserializer=beer__likes__pb2.UpdateLikeRequest.FromString,
+          response_serializer=beer__likes__pb2.Like.SerializeToString,
+      ),
+      'ToggleLike': grpc.unary_unary_rpc_method_handler(
+          servicer.ToggleLike,
+          request_deserializer=beer__likes__pb2.ToggleLikeRequest.FromString,
+          response_serializer=beer__likes__pb2.ToggleLikeResponse.SerializeToString,
+      ),
+      'WatchLikes': grpc.unary_stream_rpc_method_handler(
+          servicer.WatchLikes,
+          request_deserializer=beer__likes__pb2.LikesQuery.FromString,
+          response_serializer=beer__likes__pb2.LikeEvent.SerializeToString,
+      ),
   }
   generic_handler = grpc.method_handlers_generic_handler(
       'beerlikes.BeerLikes', rpc_method_handlers)
   server.add_generic_rpc_handlers((generic_handler,))
+
+
+class BeerLikesAdminStub(object):
+  """Administrative interface exported by the server.
+  """
+
+  def __init__(self, channel):
+    """Constructor.
+
+    Args:
+      channel: A grpc.Channel.
+    """
+    self.ExportLikes = channel.unary_stream(
+        '/beerlikes.BeerLikesAdmin/ExportLikes',
+        request_serializer=beer__likes__pb2.ExportRequest.SerializeToString,
+        response_deserializer=beer__likes__pb2.ExportChunk.FromString,
+        )
+    self.ImportLikes = channel.stream_unary(
+        '/beerlikes.BeerLikesAdmin/ImportLikes',
+        request_serializer=beer__likes__pb2.Like.SerializeToString,
+        response_deserializer=beer__likes__pb2.ImportSummary.FromString,
+        )
+    self.SetRefTypeParent = channel.unary_unary(
+        '/beerlikes.BeerLikesAdmin/SetRefTypeParent',
+        request_serializer=beer__likes__pb2.RefTypeParent.SerializeToString,
+        response_deserializer=beer__likes__pb2.RefTypeParent.FromString,
+        )
+    self.ListRefTypeParents = channel.unary_stream(
+        '/beerlikes.BeerLikesAdmin/ListRefTypeParents',
+        request_serializer=beer__likes__pb2.RefTypeParentsQuery.SerializeToString,
+        response_deserializer=beer__likes__pb2.RefTypeParent.FromString,
+        )
+    self.ListTenants = channel.unary_stream(
+        '/beerlikes.BeerLikesAdmin/ListTenants',
+        request_serializer=beer__likes__pb2.TenantsQuery.SerializeToString,
+        response_deserializer=beer__likes__pb2.TenantStats.FromString,
+        )
+    self.SetLogging = channel.unary_unary(
+        '/beerlikes.BeerLikesAdmin/SetLogging',
+        request_serializer=beer__likes__pb2.LoggingConfig.SerializeToString,
+        response_deserializer=beer__likes__pb2.LoggingConfig.FromString,
+        )
+    self.WatchAudit = channel.unary_stream(
+        '/beerlikes.BeerLikesAdmin/WatchAudit',
+        request_serializer=beer__likes__pb2.AuditQuery.SerializeToString,
+        response_deserializer=beer__likes__pb2.AuditRecord.FromString,
+        )
+
+
+class BeerLikesAdminServicer(object):
+  """Administrative interface exported by the server.
+  """
+
+  def ExportLikes(self, request, context):
+    """Stream a consistent snapshot of all the likes, encoded in the requested
+    format. The concatenated chunks make up the whole file.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def ImportLikes(self, request_iterator, context):
+    """Import a stream of likes. An imported like replaces any like with the
+    same id, so an interrupted import can be sent again. Invalid likes are
+    rejected and reported in the summary; the others are imported.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def SetRefTypeParent(self, request, context):
+    """Set the parent of a RefType, e.g. the brewery of a beer, or remove it if
+    the parent is not set. Rollup summaries include the likes of the
+    descendants of a RefType. A parent that would make a cycle is rejected.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def ListRefTypeParents(self, request, context):
+    """Stream the parents of the RefTypes with the given name, or of every
+    RefType when it is empty.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def ListTenants(self, request, context):
+    """Stream the counts of likes and requests of each tenant. Unlike the other
+    calls it is not scoped by the tenant of the request.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def SetLogging(self, request, context):
+    """Change the log level and format of the server, leaving the empty fields
+    unchanged, and return the current ones. Like ListTenants it is not scoped
+    by tenant.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+  def WatchAudit(self, request, context):
+    """Stream the audit records of the writes of the tenant of the request as
+    they are made. It fails with FAILED_PRECONDITION unless the stream audit
+    sink is enabled.
+    """
+    context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+    context.set_details('Method not implemented!')
+    raise NotImplementedError('Method not implemented!')
+
+
+def add_BeerLikesAdminServicer_to_server(servicer, server):
+  rpc_method_handlers = {
+      'ExportLikes': grpc.unary_stream_rpc_method_handler(
+          servicer.ExportLikes,
+          request_deserializer=beer__likes__pb2.ExportRequest.FromString,
+          response_serializer=beer__likes__pb2.ExportChunk.SerializeToString,
+      ),
+      'ImportLikes': grpc.stream_unary_rpc_method_handler(
+          servicer.ImportLikes,
+          request_deserializer=beer__likes__pb2.Like.FromString,
+          response_serializer=beer__likes__pb2.ImportSummary.SerializeToString,
+      ),
+      'SetRefTypeParent': grpc.unary_unary_rpc_method_handler(
+          servicer.SetRefTypeParent,
+          request_deserializer=beer__likes__pb2.RefTypeParent.FromString,
+          response_serializer=beer__likes__pb2.RefTypeParent.SerializeToString,
+      ),
+      'ListRefTypeParents': grpc.unary_stream_rpc_method_handler(
+          servicer.ListRefTypeParents,
+          request_deserializer=beer__likes__pb2.RefTypeParentsQuery.FromString,
+          response_serializer=beer__likes__pb2.RefTypeParent.SerializeToString,
+      ),
+      'ListTenants': grpc.unary_stream_rpc_method_handler(
+          servicer.ListTenants,
+          request_deserializer=beer__likes__pb2.TenantsQuery.FromString,
+          response_serializer=beer__likes__pb2.TenantStats.SerializeToString,
+      ),
+      'SetLogging': grpc.unary_unary_rpc_method_handler(
+          servicer.SetLogging,
+          request_deserializer=beer__likes__pb2.LoggingConfig.FromString,
+          response_serializer=beer__likes__pb2.LoggingConfig.SerializeToString,
+      ),
+      'WatchAudit': grpc.unary_stream_rpc_method_handler(
+          servicer.WatchAudit,
+          request_deserializer=beer__likes__pb2.AuditQuery.FromString,
+          response_serializer=beer__likes__pb2.AuditRecord.SerializeToString,
+      ),
+  }
+  generic_handler = grpc.method_handlers_generic_handler(
+      'beerlikes.BeerLikesAdmin', rpc_method_handlers)
+  server.add_generic_rpc_handlers((generic_handler,))
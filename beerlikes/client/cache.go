@@ -0,0 +1,146 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package client
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// VersionHeader is the response header with the version of the likes of the
+// RefType a call read or changed.
+const VersionHeader = "x-likes-version"
+
+// summaryCache holds the summaries returned by GetLikesSummary until they
+// are invalidated. An expired summary is revalidated with if_version_changed.
+type summaryCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*cacheEntry
+	// generation changes on every invalidation, so that a summary fetched
+	// before an invalidation is not cached after it.
+	generation uint64
+}
+
+type cacheEntry struct {
+	summary *pb.LikesSummary
+	expires time.Time
+}
+
+func newSummaryCache(ttl time.Duration) *summaryCache {
+	return &summaryCache{ttl: ttl, entries: make(map[string]*cacheEntry)}
+}
+
+func cacheKey(refType *pb.RefType) string {
+	return refType.GetName() + "/" + refType.GetId()
+}
+
+// headerVersion returns the version in the response header md, or 0.
+func headerVersion(md metadata.MD) uint64 {
+	if values := md.Get(VersionHeader); len(values) > 0 {
+		version, _ := strconv.ParseUint(values[0], 10, 64)
+		return version
+	}
+	return 0
+}
+
+// get returns a copy of the cached summary of a RefType, or nil, and whether
+// it has not expired yet.
+func (sc *summaryCache) get(refType *pb.RefType) (*pb.LikesSummary, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	entry, ok := sc.entries[cacheKey(refType)]
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(entry.summary).(*pb.LikesSummary), time.Now().Before(entry.expires)
+}
+
+// start returns the generation to pass to put for a summary about to be fetched.
+func (sc *summaryCache) start() uint64 {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.generation
+}
+
+// put caches a summary fetched or revalidated in the given generation for
+// another ttl, unless the cache was invalidated since.
+func (sc *summaryCache) put(generation uint64, refType *pb.RefType, summary *pb.LikesSummary) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if generation != sc.generation {
+		return
+	}
+	sc.entries[cacheKey(refType)] = &cacheEntry{
+		summary: proto.Clone(summary).(*pb.LikesSummary),
+		expires: time.Now().Add(sc.ttl),
+	}
+}
+
+// observe invalidates the summary of a RefType if a response reported a
+// newer version of its likes.
+func (sc *summaryCache) observe(refType *pb.RefType, version uint64) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	key := cacheKey(refType)
+	if entry, ok := sc.entries[key]; ok && entry.summary.Version < version {
+		delete(sc.entries, key)
+		sc.generation++
+	}
+}
+
+// remove invalidates the summary of a RefType.
+func (sc *summaryCache) remove(refType *pb.RefType) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	delete(sc.entries, cacheKey(refType))
+	sc.generation++
+}
+
+// clear invalidates every summary.
+func (sc *summaryCache) clear() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.entries = make(map[string]*cacheEntry)
+	sc.generation++
+}
+
+// invalidateCache watches the like events and invalidates the summaries of
+// the RefTypes they change until ctx is done. Events may be missed while the
+// watch is reconnecting, so the whole cache is cleared then.
+func (c *Client) invalidateCache(ctx context.Context) {
+	backoff := 100 * time.Millisecond
+	for {
+		c.Watch(ctx, nil, func(event *pb.LikeEvent) error {
+			backoff = 100 * time.Millisecond
+			if event.Like != nil {
+				c.cache.remove(event.Like.RefType)
+			}
+			if event.PreviousRefType != nil {
+				c.cache.remove(event.PreviousRefType)
+			}
+			return nil
+		})
+		c.cache.clear()
+		select {
+		case <-time.After(time.Duration(rand.Int63n(int64(backoff)) + 1)):
+		case <-ctx.Done():
+			return
+		}
+		if backoff *= 2; backoff > 5*time.Second {
+			backoff = 5 * time.Second
+		}
+	}
+}
@@ -0,0 +1,799 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+// Package client is a Go client library for the beer likes service.
+//
+// It wraps the generated BeerLikesClient with typed helpers that apply a
+// default deadline and the timeouts, retry and hedging policies of a
+// ServiceConfig, and drain the server streams:
+//
+//	c, err := client.Dial("127.0.0.1:10000")
+//	if err != nil {
+//		...
+//	}
+//	defer c.Close()
+//	likes, err := c.ListAll(ctx, &pb.RefType{Name: "beer", Id: "1"})
+package client
+
+import (
+	crand "crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// IdempotencyKeyHeader is the request metadata key of the idempotency key of
+// a write. The server returns the original response to a write repeated with
+// the same key.
+const IdempotencyKeyHeader = "idempotency-key"
+
+// RequestIDHeader is the request and response metadata key of the id that
+// correlates a call with the log entry of the server. Every call sends a new id
+// unless its context has one, and the server returns it in the response header
+// and trailer and in the details of an error, see RequestID.
+const RequestIDHeader = "x-request-id"
+
+// TenantHeader is the request metadata key of the tenant whose likes a call
+// reads and writes. Calls without it use the default tenant of the server.
+const TenantHeader = "x-tenant-id"
+
+// PrincipalHeader is the request metadata key of who makes a call, recorded
+// in the audit records of its writes unless the client has a certificate.
+const PrincipalHeader = "x-principal"
+
+// DefaultTimeout is the deadline of a call whose context has none, unless its
+// method has a timeout in the ServiceConfig.
+const DefaultTimeout = 10 * time.Second
+
+// Client is a beer likes client. It is safe for concurrent use.
+type Client struct {
+	conn  *grpc.ClientConn
+	likes pb.BeerLikesClient
+	admin pb.BeerLikesAdminClient
+
+	timeout   time.Duration
+	config    *ServiceConfig
+	md        metadata.MD
+	cache     *summaryCache      // nil unless WithSummaryCache is given
+	stopCache context.CancelFunc // stops the cache invalidation
+
+	tls                bool
+	caFile             string
+	serverHostOverride string
+	certFile, keyFile  string // the client certificate, if any
+	dialOpts           []grpc.DialOption
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithTLS connects with TLS, verifying the server with the CA certificates in
+// caFile, or the system roots if it is empty. serverHostOverride, if set,
+// replaces the server name used to verify the certificate.
+func WithTLS(caFile, serverHostOverride string) Option {
+	return func(c *Client) {
+		c.tls = true
+		c.caFile = caFile
+		c.serverHostOverride = serverHostOverride
+	}
+}
+
+// WithClientCert connects with TLS and authenticates the client with the
+// certificate and key in certFile and keyFile. The server takes the common
+// name of the certificate as the principal of the calls.
+func WithClientCert(certFile, keyFile string) Option {
+	return func(c *Client) {
+		c.tls = true
+		c.certFile = certFile
+		c.keyFile = keyFile
+	}
+}
+
+// WithTimeout sets the deadline of calls whose context has none. Watch is not
+// affected.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithServiceConfig sets the per-method timeouts, retry and hedging policies,
+// replacing DefaultServiceConfig.
+func WithServiceConfig(config *ServiceConfig) Option {
+	return func(c *Client) {
+		c.config = config
+	}
+}
+
+// WithSummaryCache caches the summaries returned by Summary for up to ttl.
+// A cached summary is invalidated early when the server reports a change to
+// the likes of its RefType, through WatchLikes or the VersionHeader of a
+// response.
+func WithSummaryCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = newSummaryCache(ttl)
+	}
+}
+
+// WithMetadata adds key value pairs to the metadata of every call.
+func WithMetadata(kv ...string) Option {
+	return func(c *Client) {
+		c.md = metadata.Join(c.md, metadata.Pairs(kv...))
+	}
+}
+
+// WithTenant scopes every call to the likes of a tenant.
+func WithTenant(tenant string) Option {
+	return WithMetadata(TenantHeader, tenant)
+}
+
+// WithPrincipal sends principal as who makes every call.
+func WithPrincipal(principal string) Option {
+	return WithMetadata(PrincipalHeader, principal)
+}
+
+// WithDialOptions adds options to the underlying grpc.Dial.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(c *Client) {
+		c.dialOpts = append(c.dialOpts, opts...)
+	}
+}
+
+// Dial connects to the beer likes service at target.
+func Dial(target string, opts ...Option) (*Client, error) {
+	c := &Client{
+		timeout: DefaultTimeout,
+		config:  DefaultServiceConfig(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	dialOpts := []grpc.DialOption{grpc.WithInsecure()}
+	if c.tls {
+		config := &tls.Config{ServerName: c.serverHostOverride}
+		if c.caFile != "" {
+			b, err := ioutil.ReadFile(c.caFile)
+			if err != nil {
+				return nil, err
+			}
+			config.RootCAs = x509.NewCertPool()
+			if !config.RootCAs.AppendCertsFromPEM(b) {
+				return nil, fmt.Errorf("no CA certificates in %s", c.caFile)
+			}
+		}
+		if c.certFile != "" {
+			cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+			if err != nil {
+				return nil, err
+			}
+			config.Certificates = []tls.Certificate{cert}
+		}
+		dialOpts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(config))}
+	}
+	conn, err := grpc.Dial(target, append(dialOpts, c.dialOpts...)...)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	c.likes = pb.NewBeerLikesClient(conn)
+	c.admin = pb.NewBeerLikesAdminClient(conn)
+	if c.cache != nil {
+		var ctx context.Context
+		ctx, c.stopCache = context.WithCancel(context.Background())
+		go c.invalidateCache(ctx)
+	}
+	return c, nil
+}
+
+// Conn returns the underlying connection, for calls the Client does not wrap.
+func (c *Client) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	if c.stopCache != nil {
+		c.stopCache()
+	}
+	return c.conn.Close()
+}
+
+// CallMetadata returns a context that adds key value pairs to the metadata of
+// the calls made with it.
+func CallMetadata(ctx context.Context, kv ...string) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return metadata.NewOutgoingContext(ctx, metadata.Join(md, metadata.Pairs(kv...)))
+}
+
+// WithIdempotencyKey returns a context that sends key as the idempotency key
+// of the writes made with it. Create and Delete otherwise generate a key per
+// call, which only covers the retries of that call.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return CallMetadata(ctx, IdempotencyKeyHeader, key)
+}
+
+// WithRequestID returns a context that sends id as the request id of the
+// calls made with it, instead of a new id per call.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return CallMetadata(ctx, RequestIDHeader, id)
+}
+
+// RequestID returns the request id in the details of an error returned by
+// the server, or an empty string if it has none.
+func RequestID(err error) string {
+	st, _ := status.FromError(err)
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RequestInfo); ok {
+			return info.RequestId
+		}
+	}
+	return ""
+}
+
+// newRequestID returns a random request id.
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := crand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// idempotent returns ctx with a new idempotency key unless it has one.
+func idempotent(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if len(md.Get(IdempotencyKeyHeader)) > 0 {
+		return ctx
+	}
+	b := make([]byte, 16)
+	if _, err := crand.Read(b); err != nil {
+		return ctx
+	}
+	return WithIdempotencyKey(ctx, hex.EncodeToString(b))
+}
+
+// callContext returns ctx with the client metadata, a request id unless it
+// has one, and the timeout of the method config. Without one the default
+// deadline is applied if ctx has none and deadline is true.
+func (c *Client) callContext(ctx context.Context, mc *MethodConfig, deadline bool) (context.Context, context.CancelFunc) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = metadata.Join(c.md, md)
+	if len(md.Get(RequestIDHeader)) == 0 {
+		if id := newRequestID(); id != "" {
+			md.Set(RequestIDHeader, id)
+		}
+	}
+	ctx = metadata.NewOutgoingContext(ctx, md)
+	if mc != nil && mc.Timeout > 0 {
+		return context.WithTimeout(ctx, time.Duration(mc.Timeout))
+	}
+	if _, ok := ctx.Deadline(); !ok && deadline && c.timeout > 0 {
+		return context.WithTimeout(ctx, c.timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// attempt makes one attempt of a call.
+type attempt func(ctx context.Context) (interface{}, error)
+
+// call makes a call with the retry or hedging policy of the method config.
+func call(ctx context.Context, mc *MethodConfig, fn attempt) (interface{}, error) {
+	switch {
+	case mc != nil && mc.HedgingPolicy != nil:
+		return hedge(ctx, mc.HedgingPolicy, fn)
+	case mc != nil && mc.RetryPolicy != nil:
+		return retry(ctx, mc.RetryPolicy, fn)
+	default:
+		return fn(ctx)
+	}
+}
+
+// retry makes attempts until one succeeds, fails with a code that is not
+// retryable, the attempts run out or ctx is done.
+func retry(ctx context.Context, p *RetryPolicy, fn attempt) (interface{}, error) {
+	attempts := p.MaxAttempts
+	if attempts > maxAttempts {
+		attempts = maxAttempts
+	}
+	backoff := float64(p.InitialBackoff)
+	for n := 1; ; n++ {
+		v, err := fn(ctx)
+		if err == nil || n >= attempts || !hasCode(p.RetryableStatusCodes, status.Code(err)) {
+			return v, err
+		}
+		// A random delay up to the backoff keeps clients that failed together
+		// from retrying together.
+		select {
+		case <-time.After(time.Duration(rand.Int63n(int64(backoff) + 1))):
+		case <-ctx.Done():
+			return v, err
+		}
+		backoff = math.Min(backoff*p.BackoffMultiplier, float64(p.MaxBackoff))
+	}
+}
+
+// hedge starts an attempt every hedging delay, or right after an attempt fails
+// with a non-fatal code, until the attempts run out. The first success or
+// fatal error is returned and the other attempts are canceled.
+func hedge(ctx context.Context, p *HedgingPolicy, fn attempt) (interface{}, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	attempts := p.MaxAttempts
+	if attempts > maxAttempts {
+		attempts = maxAttempts
+	}
+	type result struct {
+		v   interface{}
+		err error
+	}
+	results := make(chan result, attempts)
+	next := time.After(0)
+	sent, pending := 0, 0
+	for {
+		select {
+		case <-next:
+			sent++
+			pending++
+			go func() {
+				v, err := fn(ctx)
+				results <- result{v, err}
+			}()
+			next = nil
+			if sent < attempts {
+				next = time.After(time.Duration(p.HedgingDelay))
+			}
+		case r := <-results:
+			pending--
+			if r.err == nil || !hasCode(p.NonFatalStatusCodes, status.Code(r.err)) {
+				return r.v, r.err
+			}
+			if sent < attempts {
+				next = time.After(0)
+			} else if pending == 0 {
+				return r.v, r.err
+			}
+		}
+	}
+}
+
+// Get returns the like with the given id.
+func (c *Client) Get(ctx context.Context, id string) (*pb.Like, error) {
+	return c.GetFields(ctx, id)
+}
+
+// GetFields returns only the given fields of the like with the given id, e.g.
+// "id" and "liked", or every field if there are none.
+func (c *Client) GetFields(ctx context.Context, id string, paths ...string) (*pb.Like, error) {
+	mc := c.config.lookup(likesService, "GetLike")
+	ctx, cancel := c.callContext(ctx, mc, true)
+	defer cancel()
+	v, err := call(ctx, mc, func(ctx context.Context) (interface{}, error) {
+		return c.likes.GetLike(ctx, &pb.LikeQuery{Id: id, ReadMask: fieldMask(paths)})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*pb.Like), nil
+}
+
+// ListAll returns all the likes of a RefType. Each attempt reads the whole
+// stream within the deadline.
+func (c *Client) ListAll(ctx context.Context, refType *pb.RefType) ([]*pb.Like, error) {
+	return c.List(ctx, &pb.LikesQuery{RefType: refType})
+}
+
+// ListAllIncludingDeleted returns all the likes of a RefType, including the
+// deleted likes that were not purged yet.
+func (c *Client) ListAllIncludingDeleted(ctx context.Context, refType *pb.RefType) ([]*pb.Like, error) {
+	return c.List(ctx, &pb.LikesQuery{RefType: refType, IncludeDeleted: true})
+}
+
+// Latest returns the n most recently created likes of a RefType.
+func (c *Client) Latest(ctx context.Context, refType *pb.RefType, n int32) ([]*pb.Like, error) {
+	return c.List(ctx, &pb.LikesQuery{RefType: refType, OrderBy: pb.LikesQuery_CREATED_AT_DESC, Limit: n})
+}
+
+// List returns all the likes matched by a query, e.g. the likes of every
+// RefType with a name and an id prefix:
+//
+//	likes, err := c.List(ctx, &pb.LikesQuery{RefType: &pb.RefType{Name: "beer"}, IdPrefix: "12"})
+//
+// or the 20 latest likes of a user:
+//
+//	likes, err := c.List(ctx, &pb.LikesQuery{RefType: &pb.RefType{Name: "beer"}, UserId: "u1", OrderBy: pb.LikesQuery_CREATED_AT_DESC, Limit: 20})
+func (c *Client) List(ctx context.Context, query *pb.LikesQuery) ([]*pb.Like, error) {
+	mc := c.config.lookup(likesService, "ListLikes")
+	ctx, cancel := c.callContext(ctx, mc, true)
+	defer cancel()
+	v, err := call(ctx, mc, func(ctx context.Context) (interface{}, error) {
+		stream, err := c.likes.ListLikes(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		if md, err := stream.Header(); err == nil {
+			c.observe(query.RefType, md)
+		}
+		var likes []*pb.Like
+		for {
+			like, err := stream.Recv()
+			if err == io.EOF {
+				return likes, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			likes = append(likes, like)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*pb.Like), nil
+}
+
+// Summary returns the summary of a RefType. With the cache enabled a cached
+// summary is returned until it expires, after which the server is only asked
+// for the likes if they changed.
+func (c *Client) Summary(ctx context.Context, refType *pb.RefType) (*pb.LikesSummary, error) {
+	query := &pb.LikesQuery{RefType: refType}
+	var cached *pb.LikesSummary
+	var generation uint64
+	if c.cache != nil {
+		var fresh bool
+		if cached, fresh = c.cache.get(refType); fresh {
+			return cached, nil
+		}
+		generation = c.cache.start()
+		if cached != nil {
+			query.IfVersionChanged = cached.Version
+		}
+	}
+	mc := c.config.lookup(likesService, "GetLikesSummary")
+	ctx, cancel := c.callContext(ctx, mc, true)
+	defer cancel()
+	v, err := call(ctx, mc, func(ctx context.Context) (interface{}, error) {
+		return c.likes.GetLikesSummary(ctx, query)
+	})
+	if err != nil {
+		return nil, err
+	}
+	summary := v.(*pb.LikesSummary)
+	if summary.NotModified && cached != nil {
+		summary = cached
+	}
+	if c.cache != nil {
+		c.cache.put(generation, refType, summary)
+	}
+	return summary, nil
+}
+
+// RollupSummary returns the summary of a RefType including the likes of its
+// descendants. Rollup summaries are not cached.
+func (c *Client) RollupSummary(ctx context.Context, refType *pb.RefType) (*pb.LikesSummary, error) {
+	mc := c.config.lookup(likesService, "GetLikesSummary")
+	ctx, cancel := c.callContext(ctx, mc, true)
+	defer cancel()
+	v, err := call(ctx, mc, func(ctx context.Context) (interface{}, error) {
+		return c.likes.GetLikesSummary(ctx, &pb.LikesQuery{RefType: refType, Rollup: true})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*pb.LikesSummary), nil
+}
+
+// observe passes the version in a response header to the cache.
+func (c *Client) observe(refType *pb.RefType, md metadata.MD) {
+	if c.cache != nil {
+		c.cache.observe(refType, headerVersion(md))
+	}
+}
+
+// Create creates a like. It is only retried if the ServiceConfig has a retry
+// policy for CreateLike; the attempts share an idempotency key, so the like
+// is created once.
+func (c *Client) Create(ctx context.Context, like *pb.Like) (*pb.Like, error) {
+	mc := c.config.lookup(likesService, "CreateLike")
+	ctx, cancel := c.callContext(idempotent(ctx), mc, true)
+	defer cancel()
+	v, err := call(ctx, mc, func(ctx context.Context) (interface{}, error) {
+		var md metadata.MD
+		created, err := c.likes.CreateLike(ctx, like, grpc.Header(&md))
+		if err == nil {
+			c.observe(created.RefType, md)
+		}
+		return created, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*pb.Like), nil
+}
+
+// Delete deletes the like with the given id. It is only retried if the
+// ServiceConfig has a retry policy for DeleteLike; the attempts share an
+// idempotency key.
+func (c *Client) Delete(ctx context.Context, id string) (*pb.Like, error) {
+	return c.DeleteIfVersion(ctx, id, 0)
+}
+
+// DeleteIfVersion deletes the like with the given id if it is still at the
+// expected version, and fails with Aborted otherwise.
+func (c *Client) DeleteIfVersion(ctx context.Context, id string, expectedVersion uint64) (*pb.Like, error) {
+	mc := c.config.lookup(likesService, "DeleteLike")
+	ctx, cancel := c.callContext(idempotent(ctx), mc, true)
+	defer cancel()
+	v, err := call(ctx, mc, func(ctx context.Context) (interface{}, error) {
+		var md metadata.MD
+		deleted, err := c.likes.DeleteLike(ctx, &pb.LikeQuery{Id: id, ExpectedVersion: expectedVersion}, grpc.Header(&md))
+		if err == nil {
+			c.observe(deleted.RefType, md)
+		}
+		return deleted, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*pb.Like), nil
+}
+
+// Undelete restores a deleted like that was not purged yet. It shares the
+// retry policy and idempotency key handling of Delete.
+func (c *Client) Undelete(ctx context.Context, id string) (*pb.Like, error) {
+	mc := c.config.lookup(likesService, "UndeleteLike")
+	ctx, cancel := c.callContext(idempotent(ctx), mc, true)
+	defer cancel()
+	v, err := call(ctx, mc, func(ctx context.Context) (interface{}, error) {
+		var md metadata.MD
+		restored, err := c.likes.UndeleteLike(ctx, &pb.LikeQuery{Id: id}, grpc.Header(&md))
+		if err == nil {
+			c.observe(restored.RefType, md)
+		}
+		return restored, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*pb.Like), nil
+}
+
+// Toggle creates, flips or deletes the like of a user for a RefType to reach
+// the target state, and returns the like with the new counts of the RefType.
+// A TOGGLE target is not idempotent, so retried attempts share an idempotency
+// key.
+func (c *Client) Toggle(ctx context.Context, userID string, refType *pb.RefType, target pb.ToggleLikeRequest_Target) (*pb.ToggleLikeResponse, error) {
+	mc := c.config.lookup(likesService, "ToggleLike")
+	ctx, cancel := c.callContext(idempotent(ctx), mc, true)
+	defer cancel()
+	v, err := call(ctx, mc, func(ctx context.Context) (interface{}, error) {
+		var md metadata.MD
+		resp, err := c.likes.ToggleLike(ctx, &pb.ToggleLikeRequest{UserId: userID, RefType: refType, Target: target}, grpc.Header(&md))
+		if err == nil {
+			c.observe(refType, md)
+		}
+		return resp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*pb.ToggleLikeResponse), nil
+}
+
+// Update changes whether a like is a like or a dislike, and its RefType if it
+// is set, if the like is still at the expected version. It fails with Aborted
+// otherwise; an expected version of 0 skips the check.
+func (c *Client) Update(ctx context.Context, like *pb.Like, expectedVersion uint64) (*pb.Like, error) {
+	return c.UpdateFields(ctx, like, expectedVersion)
+}
+
+// UpdateFields is Update that only changes the given fields of the like: liked,
+// reaction, value and ref_type. With no fields it is the same as Update.
+func (c *Client) UpdateFields(ctx context.Context, like *pb.Like, expectedVersion uint64, paths ...string) (*pb.Like, error) {
+	mc := c.config.lookup(likesService, "UpdateLike")
+	ctx, cancel := c.callContext(idempotent(ctx), mc, true)
+	defer cancel()
+	v, err := call(ctx, mc, func(ctx context.Context) (interface{}, error) {
+		var md metadata.MD
+		updated, err := c.likes.UpdateLike(ctx, &pb.UpdateLikeRequest{Like: like, ExpectedVersion: expectedVersion, UpdateMask: fieldMask(paths)}, grpc.Header(&md))
+		if err == nil {
+			c.observe(updated.RefType, md)
+		}
+		return updated, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*pb.Like), nil
+}
+
+// fieldMask returns a FieldMask of the paths, or nil if there are none.
+func fieldMask(paths []string) *field_mask.FieldMask {
+	if len(paths) == 0 {
+		return nil
+	}
+	return &field_mask.FieldMask{Paths: paths}
+}
+
+// Watch calls fn with the like events of a RefType, or of every RefType if it
+// is nil, until ctx is done, the stream fails or fn returns an error. The
+// default deadline does not apply. The call is retried by the WatchLikes
+// retry policy only until the first event; after that events may have been
+// missed, so the error is returned instead.
+func (c *Client) Watch(ctx context.Context, refType *pb.RefType, fn func(*pb.LikeEvent) error) error {
+	mc := c.config.lookup(likesService, "WatchLikes")
+	ctx, cancel := c.callContext(ctx, mc, false)
+	defer cancel()
+	var stream pb.BeerLikes_WatchLikesClient
+	var event *pb.LikeEvent
+	_, err := call(ctx, mc, func(ctx context.Context) (v interface{}, err error) {
+		if stream, err = c.likes.WatchLikes(ctx, &pb.LikesQuery{RefType: refType}); err != nil {
+			return nil, err
+		}
+		event, err = stream.Recv()
+		return nil, err
+	})
+	for err == nil {
+		if err = fn(event); err == nil {
+			event, err = stream.Recv()
+		}
+	}
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// Export writes a snapshot of all the likes to w in the given format.
+func (c *Client) Export(ctx context.Context, format pb.ExportRequest_Format, w io.Writer) error {
+	ctx, cancel := c.callContext(ctx, c.config.lookup(adminService, "ExportLikes"), true)
+	defer cancel()
+	stream, err := c.admin.ExportLikes(ctx, &pb.ExportRequest{Format: format})
+	if err != nil {
+		return err
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(chunk.Data); err != nil {
+			return err
+		}
+	}
+}
+
+// SetParent sets the parent of a RefType, or removes it if parent is nil.
+func (c *Client) SetParent(ctx context.Context, refType, parent *pb.RefType) (*pb.RefTypeParent, error) {
+	mc := c.config.lookup(adminService, "SetRefTypeParent")
+	ctx, cancel := c.callContext(ctx, mc, true)
+	defer cancel()
+	v, err := call(ctx, mc, func(ctx context.Context) (interface{}, error) {
+		return c.admin.SetRefTypeParent(ctx, &pb.RefTypeParent{RefType: refType, Parent: parent})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*pb.RefTypeParent), nil
+}
+
+// Parents returns the parents of the RefTypes with the given name, or of every
+// RefType if it is empty.
+func (c *Client) Parents(ctx context.Context, name string) ([]*pb.RefTypeParent, error) {
+	mc := c.config.lookup(adminService, "ListRefTypeParents")
+	ctx, cancel := c.callContext(ctx, mc, true)
+	defer cancel()
+	v, err := call(ctx, mc, func(ctx context.Context) (interface{}, error) {
+		stream, err := c.admin.ListRefTypeParents(ctx, &pb.RefTypeParentsQuery{Name: name})
+		if err != nil {
+			return nil, err
+		}
+		var parents []*pb.RefTypeParent
+		for {
+			parent, err := stream.Recv()
+			if err == io.EOF {
+				return parents, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			parents = append(parents, parent)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*pb.RefTypeParent), nil
+}
+
+// Tenants returns the counts of likes and requests of the tenant with the
+// given name, or of every tenant if it is empty.
+func (c *Client) Tenants(ctx context.Context, name string) ([]*pb.TenantStats, error) {
+	mc := c.config.lookup(adminService, "ListTenants")
+	ctx, cancel := c.callContext(ctx, mc, true)
+	defer cancel()
+	v, err := call(ctx, mc, func(ctx context.Context) (interface{}, error) {
+		stream, err := c.admin.ListTenants(ctx, &pb.TenantsQuery{Name: name})
+		if err != nil {
+			return nil, err
+		}
+		var tenants []*pb.TenantStats
+		for {
+			stats, err := stream.Recv()
+			if err == io.EOF {
+				return tenants, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			tenants = append(tenants, stats)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*pb.TenantStats), nil
+}
+
+// SetLogging changes the log level and format of the server, leaving the
+// empty ones unchanged, and returns the current ones.
+func (c *Client) SetLogging(ctx context.Context, level, format string) (*pb.LoggingConfig, error) {
+	mc := c.config.lookup(adminService, "SetLogging")
+	ctx, cancel := c.callContext(ctx, mc, true)
+	defer cancel()
+	v, err := call(ctx, mc, func(ctx context.Context) (interface{}, error) {
+		return c.admin.SetLogging(ctx, &pb.LoggingConfig{Level: level, Format: format})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*pb.LoggingConfig), nil
+}
+
+// WatchAudit calls fn with the audit records of the writes of the tenant, or
+// only of the given method if it is not empty, until ctx is done, the stream
+// fails or fn returns an error. The default deadline does not apply.
+func (c *Client) WatchAudit(ctx context.Context, method string, fn func(*pb.AuditRecord) error) error {
+	mc := c.config.lookup(adminService, "WatchAudit")
+	ctx, cancel := c.callContext(ctx, mc, false)
+	defer cancel()
+	var stream pb.BeerLikesAdmin_WatchAuditClient
+	var record *pb.AuditRecord
+	_, err := call(ctx, mc, func(ctx context.Context) (v interface{}, err error) {
+		if stream, err = c.admin.WatchAudit(ctx, &pb.AuditQuery{Method: method}); err != nil {
+			return nil, err
+		}
+		record, err = stream.Recv()
+		return nil, err
+	})
+	for err == nil {
+		if err = fn(record); err == nil {
+			record, err = stream.Recv()
+		}
+	}
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
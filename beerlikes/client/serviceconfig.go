@@ -0,0 +1,245 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	likesService = "beerlikes.BeerLikes"
+	adminService = "beerlikes.BeerLikesAdmin"
+
+	// maxAttempts caps the attempts of retry and hedging policies, as gRPC does.
+	maxAttempts = 5
+)
+
+// hedgedMethods are the idempotent reads that a hedging policy may apply to.
+var hedgedMethods = map[string]bool{
+	"GetLike":         true,
+	"ListLikes":       true,
+	"GetLikesSummary": true,
+}
+
+// ServiceConfig holds the per-method timeouts, retry and hedging policies of
+// a Client. It uses the JSON format of the gRPC service config, e.g.
+//
+//	{
+//	  "methodConfig": [{
+//	    "name": [{"service": "beerlikes.BeerLikes", "method": "GetLike"}],
+//	    "timeout": "2s",
+//	    "hedgingPolicy": {"maxAttempts": 3, "hedgingDelay": "0.1s", "nonFatalStatusCodes": ["UNAVAILABLE"]}
+//	  }]
+//	}
+//
+// A name without a method applies to every method of the service that is not
+// named on its own.
+type ServiceConfig struct {
+	MethodConfig []*MethodConfig `json:"methodConfig"`
+}
+
+// MethodConfig configures the calls of the named methods.
+type MethodConfig struct {
+	Name          []MethodName   `json:"name"`
+	Timeout       Duration       `json:"timeout"`
+	RetryPolicy   *RetryPolicy   `json:"retryPolicy"`
+	HedgingPolicy *HedgingPolicy `json:"hedgingPolicy"`
+}
+
+// MethodName names a method, or every method of a service if Method is empty.
+type MethodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+}
+
+// RetryPolicy retries a call that failed with one of the retryable codes.
+// The delay before retry n is random up to
+// min(InitialBackoff*BackoffMultiplier^(n-1), MaxBackoff).
+type RetryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       Duration `json:"initialBackoff"`
+	MaxBackoff           Duration `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []Code   `json:"retryableStatusCodes"`
+}
+
+// HedgingPolicy sends another attempt of a call every HedgingDelay until one
+// succeeds or MaxAttempts were sent. The first result that is not a non-fatal
+// error is returned and the other attempts are canceled.
+type HedgingPolicy struct {
+	MaxAttempts         int      `json:"maxAttempts"`
+	HedgingDelay        Duration `json:"hedgingDelay"`
+	NonFatalStatusCodes []Code   `json:"nonFatalStatusCodes"`
+}
+
+// Duration is a time.Duration in the JSON format of a protobuf Duration, e.g. "1.5s".
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil || !strings.HasSuffix(s, "s") {
+		return fmt.Errorf("%q is not a duration in seconds", s)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Code is a gRPC status code in the JSON format of the service config, e.g.
+// "UNAVAILABLE".
+type Code codes.Code
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Code) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	code, ok := codeNames[strings.ToUpper(s)]
+	if !ok {
+		return fmt.Errorf("unknown status code %q", s)
+	}
+	*c = Code(code)
+	return nil
+}
+
+var codeNames = map[string]codes.Code{
+	"OK":                  codes.OK,
+	"CANCELLED":           codes.Canceled,
+	"UNKNOWN":             codes.Unknown,
+	"INVALID_ARGUMENT":    codes.InvalidArgument,
+	"DEADLINE_EXCEEDED":   codes.DeadlineExceeded,
+	"NOT_FOUND":           codes.NotFound,
+	"ALREADY_EXISTS":      codes.AlreadyExists,
+	"PERMISSION_DENIED":   codes.PermissionDenied,
+	"RESOURCE_EXHAUSTED":  codes.ResourceExhausted,
+	"FAILED_PRECONDITION": codes.FailedPrecondition,
+	"ABORTED":             codes.Aborted,
+	"OUT_OF_RANGE":        codes.OutOfRange,
+	"UNIMPLEMENTED":       codes.Unimplemented,
+	"INTERNAL":            codes.Internal,
+	"UNAVAILABLE":         codes.Unavailable,
+	"DATA_LOSS":           codes.DataLoss,
+	"UNAUTHENTICATED":     codes.Unauthenticated,
+}
+
+func hasCode(list []Code, code codes.Code) bool {
+	for _, c := range list {
+		if codes.Code(c) == code {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultServiceConfig returns the config used when none is given: the reads
+// and WatchLikes are retried on UNAVAILABLE, and nothing is hedged.
+func DefaultServiceConfig() *ServiceConfig {
+	return &ServiceConfig{MethodConfig: []*MethodConfig{{
+		Name: []MethodName{
+			{Service: likesService, Method: "GetLike"},
+			{Service: likesService, Method: "ListLikes"},
+			{Service: likesService, Method: "GetLikesSummary"},
+			{Service: likesService, Method: "WatchLikes"},
+		},
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts:          3,
+			InitialBackoff:       Duration(100 * time.Millisecond),
+			MaxBackoff:           Duration(5 * time.Second),
+			BackoffMultiplier:    2,
+			RetryableStatusCodes: []Code{Code(codes.Unavailable)},
+		},
+	}}}
+}
+
+// ParseServiceConfig parses and validates a JSON service config.
+func ParseServiceConfig(data []byte) (*ServiceConfig, error) {
+	config := &ServiceConfig{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid service config: %v", err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid service config: %v", err)
+	}
+	return config, nil
+}
+
+// LoadServiceConfig reads a JSON service config from a file.
+func LoadServiceConfig(path string) (*ServiceConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseServiceConfig(data)
+}
+
+func (s *ServiceConfig) validate() error {
+	for _, mc := range s.MethodConfig {
+		if len(mc.Name) == 0 {
+			return fmt.Errorf("a methodConfig has no name")
+		}
+		if mc.Timeout < 0 {
+			return fmt.Errorf("timeout of %s is negative", mc.Name[0])
+		}
+		if mc.RetryPolicy != nil && mc.HedgingPolicy != nil {
+			return fmt.Errorf("%s has both a retryPolicy and a hedgingPolicy", mc.Name[0])
+		}
+		if p := mc.RetryPolicy; p != nil {
+			if p.MaxAttempts < 2 || p.InitialBackoff <= 0 || p.MaxBackoff <= 0 || p.BackoffMultiplier <= 0 || len(p.RetryableStatusCodes) == 0 {
+				return fmt.Errorf("retryPolicy of %s needs maxAttempts of 2 or more, positive backoffs and retryableStatusCodes", mc.Name[0])
+			}
+		}
+		if p := mc.HedgingPolicy; p != nil {
+			if p.MaxAttempts < 2 || p.HedgingDelay < 0 {
+				return fmt.Errorf("hedgingPolicy of %s needs maxAttempts of 2 or more", mc.Name[0])
+			}
+			for _, name := range mc.Name {
+				if name.Service != likesService || !hedgedMethods[name.Method] {
+					return fmt.Errorf("%s cannot be hedged, only the GetLike, ListLikes and GetLikesSummary reads can", name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// String returns the full name of the method.
+func (n MethodName) String() string {
+	if n.Method == "" {
+		return n.Service
+	}
+	return n.Service + "/" + n.Method
+}
+
+// lookup returns the config of a method, or nil if it has none.
+func (s *ServiceConfig) lookup(service, method string) *MethodConfig {
+	var serviceConfig *MethodConfig
+	for _, mc := range s.MethodConfig {
+		for _, name := range mc.Name {
+			if name.Service != service {
+				continue
+			}
+			if name.Method == method {
+				return mc
+			}
+			if name.Method == "" && serviceConfig == nil {
+				serviceConfig = mc
+			}
+		}
+	}
+	return serviceConfig
+}
@@ -0,0 +1,63 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+// Package reaction holds the rules for the reaction and rating of a like,
+// shared by the beer likes server and the tools that validate likes before
+// sending them to it.
+package reaction
+
+import (
+	"fmt"
+	"regexp"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+const (
+	Like    = "like"
+	Dislike = "dislike"
+	Rating  = "rating"
+
+	MinRating = 1
+	MaxRating = 5
+)
+
+var pattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// Normalize validates the reaction of a like and keeps it consistent with
+// liked: an empty reaction is set from liked, and liked is set from a like or
+// dislike reaction. Other reactions keep their liked as it is.
+func Normalize(like *pb.Like) error {
+	switch like.Reaction {
+	case "":
+		like.Reaction = Vote(like.Liked)
+	case Like, Dislike:
+		like.Liked = like.Reaction == Like
+	case Rating:
+		if like.Value < MinRating || like.Value > MaxRating {
+			return fmt.Errorf("a rating must be between %d and %d: %d", MinRating, MaxRating, like.Value)
+		}
+	default:
+		if !pattern.MatchString(like.Reaction) {
+			return fmt.Errorf("reaction %q must be up to 32 lower case letters, digits and underscores", like.Reaction)
+		}
+	}
+	return nil
+}
+
+// Vote returns the reaction of a like or dislike.
+func Vote(liked bool) string {
+	if liked {
+		return Like
+	}
+	return Dislike
+}
+
+// IsVote reports whether a reaction is a like or dislike rather than another
+// reaction. An empty reaction is a vote.
+func IsVote(reaction string) bool {
+	return reaction == "" || reaction == Like || reaction == Dislike
+}
@@ -0,0 +1,80 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package reaction
+
+import (
+	"strings"
+	"testing"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		like     *pb.Like
+		reaction string
+		liked    bool
+		valid    bool
+	}{
+		{"empty liked", &pb.Like{Liked: true}, Like, true, true},
+		{"empty disliked", &pb.Like{}, Dislike, false, true},
+		{"like sets liked", &pb.Like{Reaction: Like}, Like, true, true},
+		{"dislike clears liked", &pb.Like{Reaction: Dislike, Liked: true}, Dislike, false, true},
+		{"lowest rating", &pb.Like{Reaction: Rating, Value: MinRating}, Rating, false, true},
+		{"highest rating", &pb.Like{Reaction: Rating, Value: MaxRating}, Rating, false, true},
+		{"rating below the range", &pb.Like{Reaction: Rating, Value: MinRating - 1}, Rating, false, false},
+		{"rating above the range", &pb.Like{Reaction: Rating, Value: MaxRating + 1}, Rating, false, false},
+		{"custom keeps liked", &pb.Like{Reaction: "cheers", Liked: true}, "cheers", true, true},
+		{"custom keeps disliked", &pb.Like{Reaction: "want_to_try"}, "want_to_try", false, true},
+		{"longest custom", &pb.Like{Reaction: "a" + strings.Repeat("b", 31)}, "a" + strings.Repeat("b", 31), false, true},
+		{"custom too long", &pb.Like{Reaction: "a" + strings.Repeat("b", 32)}, "", false, false},
+		{"custom upper case", &pb.Like{Reaction: "Cheers"}, "", false, false},
+		{"custom starting with a digit", &pb.Like{Reaction: "1st"}, "", false, false},
+		{"custom with a space", &pb.Like{Reaction: "want to try"}, "", false, false},
+	}
+	for _, test := range tests {
+		err := Normalize(test.like)
+		if valid := err == nil; valid != test.valid {
+			t.Errorf("%s: Normalize returned %v, want valid %v", test.name, err, test.valid)
+			continue
+		}
+		if !test.valid {
+			continue
+		}
+		if test.like.Reaction != test.reaction || test.like.Liked != test.liked {
+			t.Errorf("%s: Normalize set reaction %q and liked %v, want %q and %v", test.name, test.like.Reaction, test.like.Liked, test.reaction, test.liked)
+		}
+	}
+}
+
+func TestVote(t *testing.T) {
+	if got := Vote(true); got != Like {
+		t.Errorf("Vote(true) = %q, want %q", got, Like)
+	}
+	if got := Vote(false); got != Dislike {
+		t.Errorf("Vote(false) = %q, want %q", got, Dislike)
+	}
+}
+
+func TestIsVote(t *testing.T) {
+	tests := []struct {
+		reaction string
+		want     bool
+	}{
+		{"", true},
+		{Like, true},
+		{Dislike, true},
+		{Rating, false},
+		{"cheers", false},
+	}
+	for _, test := range tests {
+		if got := IsVote(test.reaction); got != test.want {
+			t.Errorf("IsVote(%q) = %v, want %v", test.reaction, got, test.want)
+		}
+	}
+}
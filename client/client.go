@@ -4,153 +4,667 @@
  *
  */
 
-// Package main implements a simple gRPC client that demonstrates how to use gRPC-Go libraries
-// to perform unary and server streaming
+// Package main implements a command line client for the beer likes service
+// whose definition can be found in beerlikes/beer_likes.proto.
 //
-// It interacts with the beer likes service whose definition can be found in beerlikes/beer_likes.proto.
+//	client [flags] get [-fields path,...] <like id>
+//	client [flags] list [-deleted] [-fields path,...] [-prefix p | -ids id,...] [-liked | -disliked] [-user id] [-since time] [-until time] [-like_ids id,...] [-order saved|created_at_asc|created_at_desc|id] [-limit n] <ref type name> [<ref type id>]
+//	client [flags] summary [-rollup] <ref type name> <ref type id>
+//	client [flags] like [-dislike] [-reaction name [-value n]] [-user id] <ref type name> <ref type id>
+//	client [flags] toggle [-target toggle|liked|disliked|cleared] -user id <ref type name> <ref type id>
+//	client [flags] unlike [-version n] <like id>
+//	client [flags] undelete <like id>
+//	client [flags] update [-dislike] [-version n] <like id>
+//	client [flags] parent <ref type name> <ref type id> [<parent name> <parent id>]
+//	client [flags] parents [<ref type name>]
+//	client [flags] tenants [<tenant>]
+//	client [flags] logging [-level debug|info|warning|error] [-format text|json]
+//	client [flags] watch [<ref type name> <ref type id>]
+//	client [flags] audit [-method name]
+//	client [flags] export [-format json|jsonl|csv] [-file file]
+//
+// The results are printed as a table, JSON or protobuf text, selected by
+// -output. The exit code is the gRPC status code of the failed call, 0 on
+// success and InvalidArgument (3) for usage errors.
 package main
 
 import (
 	"flag"
 	"fmt"
 	"io"
-	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
 	"time"
 
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"golang.org/x/net/context"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/metadata"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/testdata"
 
 	pb "github.com/phriscage/beer-likes/beerlikes"
+	"github.com/phriscage/beer-likes/beerlikes/client"
 )
 
 var (
 	tls                = flag.Bool("tls", false, "Connection uses TLS if true, else plain TCP")
 	caFile             = flag.String("ca_file", "", "The file containning the CA root cert file")
+	certFile           = flag.String("cert_file", "", "The client certificate file, whose common name the server takes as the principal of the calls")
+	keyFile            = flag.String("key_file", "", "The key file of -cert_file")
 	port               = flag.Int("port", 10000, "The server port")
 	host               = flag.String("host", "127.0.0.1", "The server host ip")
 	serverHostOverride = flag.String("server_host_override", "x.test.youtube.com", "The server name use to verify the hostname returned by TLS handshake")
+	output             = flag.String("output", "table", "The output format: table, json or text")
+	timeout            = flag.Duration("timeout", 10*time.Second, "The deadline of each call without a timeout in the service config")
+	tenant             = flag.String("tenant", "", "The tenant whose likes are used, empty uses the default tenant")
+	principal          = flag.String("principal", "", "Who makes the calls, recorded in the audit records of the writes")
+	serviceConfig      = flag.String("service_config", "", "A JSON gRPC service config, or a file containing one, with the per-method timeouts, retry and hedging policies")
 )
 
-// printLike gets the like for the given point.
-func printLike(client pb.BeerLikesClient, query *pb.LikeQuery) {
-	log.Printf("Getting like for like (%s)", query)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	var header, trailer metadata.MD // variable to store header and trailer
-	like, err := client.GetLike(ctx, query, grpc.Header(&header), grpc.Trailer(&trailer))
+// command is a subcommand of the client.
+type command struct {
+	usage string
+	run   func(c *client.Client, args []string) error
+}
+
+var commands = map[string]command{
+	"get":      {"get [-fields path,...] <like id>", runGet},
+	"list":     {"list [-deleted] [-fields path,...] [-prefix p | -ids id,...] [-liked | -disliked] [-user id] [-since time] [-until time] [-like_ids id,...] [-order saved|created_at_asc|created_at_desc|id] [-limit n] <ref type name> [<ref type id>]", runList},
+	"summary":  {"summary [-rollup] <ref type name> <ref type id>", runSummary},
+	"like":     {"like [-dislike] [-reaction name [-value n]] [-user id] <ref type name> <ref type id>", runLike},
+	"toggle":   {"toggle [-target toggle|liked|disliked|cleared] -user id <ref type name> <ref type id>", runToggle},
+	"unlike":   {"unlike [-version n] <like id>", runUnlike},
+	"undelete": {"undelete <like id>", runUndelete},
+	"update":   {"update [-dislike] [-version n] <like id>", runUpdate},
+	"parent":   {"parent <ref type name> <ref type id> [<parent name> <parent id>]", runParent},
+	"parents":  {"parents [<ref type name>]", runParents},
+	"tenants":  {"tenants [<tenant>]", runTenants},
+	"logging":  {"logging [-level debug|info|warning|error] [-format text|json]", runLogging},
+	"watch":    {"watch [<ref type name> <ref type id>]", runWatch},
+	"audit":    {"audit [-method name]", runAudit},
+	"export":   {"export [-format json|jsonl|csv] [-file file]", runExport},
+}
+
+// usageError is returned for invalid arguments to a subcommand.
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// refTypeArgs parses the ref type name and id arguments.
+func refTypeArgs(args []string) (*pb.RefType, error) {
+	if len(args) != 2 {
+		return nil, usageError("expected a ref type name and id")
+	}
+	return &pb.RefType{Name: args[0], Id: args[1]}, nil
+}
+
+// idArg parses a single like id argument.
+func idArg(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", usageError("expected a like id")
+	}
+	return args[0], nil
+}
+
+func runGet(c *client.Client, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	fields := fs.String("fields", "", "Only return these comma separated fields of the like, e.g. id,liked")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	id, err := idArg(fs.Args())
+	if err != nil {
+		return err
+	}
+	like, err := c.GetFields(context.Background(), id, fieldPaths(*fields)...)
 	if err != nil {
-		log.Printf("%v.GetLikes(_) = _, %v: ", client, err)
-		return
+		return err
 	}
-	log.Printf("res headers: %v", header)
-	log.Printf("res trailer: %v", trailer)
-	log.Println(like)
+	return printMessages(os.Stdout, like)
 }
 
-// printLikes lists all the likes within the given bounding RefType.
-func printLikes(client pb.BeerLikesClient, query *pb.LikesQuery) {
-	startTime := time.Now()
-	log.Printf("Looking for all likes within %v", query)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	stream, err := client.ListLikes(ctx, query)
-	likesSummary := pb.LikesSummary{}
-	if err != nil {
-		log.Fatalf("%v.ListLikes(_) = _, %v", client, err)
-	}
-	// retrieve header
-	header, err := stream.Header()
-	// retrieve trailer
-	trailer := stream.Trailer()
-	log.Printf("res headers: %v", header)
-	for {
-		item, err := stream.Recv()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			log.Fatalf("%v.ListLikes(_) = _, %v", client, err)
-		}
-		// log.Println(item)
-		likesSummary.Likes = append(likesSummary.Likes, item)
-		if item.Liked {
-			likesSummary.Total++
-		} else {
-			likesSummary.Total--
+func runList(c *client.Client, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	deleted := fs.Bool("deleted", false, "Include the deleted likes that were not purged yet")
+	prefix := fs.String("prefix", "", "List the likes of the RefTypes with the name and an id with this prefix")
+	ids := fs.String("ids", "", "List the likes of the RefTypes with the name and one of these comma separated ids")
+	liked := fs.Bool("liked", false, "Only list the likes")
+	disliked := fs.Bool("disliked", false, "Only list the dislikes")
+	user := fs.String("user", "", "Only list the likes of this user")
+	since := fs.String("since", "", "Only list the likes created at or after this RFC 3339 time")
+	until := fs.String("until", "", "Only list the likes created before this RFC 3339 time")
+	likeIds := fs.String("like_ids", "", "Only list the likes with these comma separated ids")
+	order := fs.String("order", "saved", "The order of the likes: saved, created_at_asc, created_at_desc or id")
+	limit := fs.Int("limit", 0, "The most likes to list, 0 lists every like")
+	fields := fs.String("fields", "", "Only return these comma separated fields of the likes, e.g. id,liked")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	query := &pb.LikesQuery{IncludeDeleted: *deleted, IdPrefix: *prefix, UserId: *user, Limit: int32(*limit)}
+	if paths := fieldPaths(*fields); len(paths) > 0 {
+		query.ReadMask = &field_mask.FieldMask{Paths: paths}
+	}
+	if *ids != "" {
+		query.Ids = strings.Split(*ids, ",")
+	}
+	if *likeIds != "" {
+		query.LikeIds = strings.Split(*likeIds, ",")
+	}
+	switch {
+	case *liked && *disliked:
+		return usageError("-liked cannot be combined with -disliked")
+	case *liked:
+		query.Liked = pb.LikesQuery_LIKED_ONLY
+	case *disliked:
+		query.Liked = pb.LikesQuery_DISLIKED_ONLY
+	}
+	orderBy, ok := pb.LikesQuery_OrderBy_value[strings.ToUpper(*order)]
+	if !ok {
+		return usageError(fmt.Sprintf("unknown order %q", *order))
+	}
+	query.OrderBy = pb.LikesQuery_OrderBy(orderBy)
+	var err error
+	if query.CreatedAfter, err = timestampFlag("since", *since); err != nil {
+		return err
+	}
+	if query.CreatedBefore, err = timestampFlag("until", *until); err != nil {
+		return err
+	}
+	switch fs.NArg() {
+	case 1:
+		query.RefType = &pb.RefType{Name: fs.Arg(0)}
+	case 2:
+		if *prefix != "" || *ids != "" {
+			return usageError("-prefix and -ids cannot be combined with a ref type id")
 		}
+		query.RefType = &pb.RefType{Name: fs.Arg(0), Id: fs.Arg(1)}
+	default:
+		return usageError("expected a ref type name and optionally an id")
+	}
+	likes, err := c.List(context.Background(), query)
+	if err != nil {
+		return err
 	}
-	log.Printf("res trailer: %v", trailer)
-	endTime := time.Now()
-	likesSummary.ElapsedTime = uint64(endTime.Sub(startTime))
-	log.Println(likesSummary)
+	msgs := make([]proto.Message, len(likes))
+	for i, like := range likes {
+		msgs[i] = like
+	}
+	return printMessages(os.Stdout, msgs...)
 }
 
-// printLikesSummary lists all the likes within the given bounding RefType.
-func printLikesSummary(client pb.BeerLikesClient, query *pb.LikesQuery) {
-	log.Printf("Looking for all likes within %v", query)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	likesSummary, err := client.GetLikesSummary(ctx, query)
+// fieldPaths parses a comma separated list of field paths.
+func fieldPaths(fields string) []string {
+	if fields == "" {
+		return nil
+	}
+	return strings.Split(fields, ",")
+}
+
+// timestampFlag parses an optional RFC 3339 time flag.
+func timestampFlag(name, value string) (*timestamp.Timestamp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
 	if err != nil {
-		log.Fatalf("%v.GetLikesSummary(_) = _, %v: ", client, err)
+		return nil, usageError(fmt.Sprintf("-%s: %v", name, err))
 	}
-	log.Println(likesSummary)
+	ts, err := ptypes.TimestampProto(t)
+	if err != nil {
+		return nil, usageError(fmt.Sprintf("-%s: %v", name, err))
+	}
+	return ts, nil
 }
 
-// Main
-func main() {
-	flag.Parse()
-	var opts []grpc.DialOption
-	if *tls {
-		if *caFile == "" {
-			*caFile = testdata.Path("ca.pem")
+func runSummary(c *client.Client, args []string) error {
+	fs := flag.NewFlagSet("summary", flag.ContinueOnError)
+	rollup := fs.Bool("rollup", false, "Include the likes of the descendants of the RefType")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	refType, err := refTypeArgs(fs.Args())
+	if err != nil {
+		return err
+	}
+	summarize := c.Summary
+	if *rollup {
+		summarize = c.RollupSummary
+	}
+	summary, err := summarize(context.Background(), refType)
+	if err != nil {
+		return err
+	}
+	return printMessages(os.Stdout, summary)
+}
+
+func runParent(c *client.Client, args []string) error {
+	if len(args) != 2 && len(args) != 4 {
+		return usageError("expected a ref type name and id, and optionally a parent name and id")
+	}
+	refType := &pb.RefType{Name: args[0], Id: args[1]}
+	var parent *pb.RefType
+	if len(args) == 4 {
+		parent = &pb.RefType{Name: args[2], Id: args[3]}
+	}
+	link, err := c.SetParent(context.Background(), refType, parent)
+	if err != nil {
+		return err
+	}
+	return printMessages(os.Stdout, link)
+}
+
+func runParents(c *client.Client, args []string) error {
+	if len(args) > 1 {
+		return usageError("expected at most a ref type name")
+	}
+	var name string
+	if len(args) == 1 {
+		name = args[0]
+	}
+	parents, err := c.Parents(context.Background(), name)
+	if err != nil {
+		return err
+	}
+	msgs := make([]proto.Message, len(parents))
+	for i, parent := range parents {
+		msgs[i] = parent
+	}
+	return printMessages(os.Stdout, msgs...)
+}
+
+func runAudit(c *client.Client, args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	method := fs.String("method", "", "Only the records of this method, e.g. CreateLike")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	if fs.NArg() != 0 {
+		return usageError("expected no arguments")
+	}
+	return c.WatchAudit(context.Background(), *method, func(record *pb.AuditRecord) error {
+		return printMessages(os.Stdout, record)
+	})
+}
+
+func runTenants(c *client.Client, args []string) error {
+	if len(args) > 1 {
+		return usageError("expected at most a tenant")
+	}
+	var name string
+	if len(args) == 1 {
+		name = args[0]
+	}
+	tenants, err := c.Tenants(context.Background(), name)
+	if err != nil {
+		return err
+	}
+	msgs := make([]proto.Message, len(tenants))
+	for i, stats := range tenants {
+		msgs[i] = stats
+	}
+	return printMessages(os.Stdout, msgs...)
+}
+
+func runLogging(c *client.Client, args []string) error {
+	fs := flag.NewFlagSet("logging", flag.ContinueOnError)
+	level := fs.String("level", "", "The log level to set, empty leaves it unchanged")
+	format := fs.String("format", "", "The log format to set, empty leaves it unchanged")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	if fs.NArg() != 0 {
+		return usageError("expected no arguments")
+	}
+	config, err := c.SetLogging(context.Background(), *level, *format)
+	if err != nil {
+		return err
+	}
+	return printMessages(os.Stdout, config)
+}
+
+func runLike(c *client.Client, args []string) error {
+	fs := flag.NewFlagSet("like", flag.ContinueOnError)
+	dislike := fs.Bool("dislike", false, "Record a dislike instead of a like")
+	reaction := fs.String("reaction", "", "Record another reaction, e.g. cheers, want_to_try or rating")
+	value := fs.Int("value", 0, "The value of the reaction, 1 to 5 stars for a rating")
+	user := fs.String("user", "", "The id of the user who likes the RefType")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	refType, err := refTypeArgs(fs.Args())
+	if err != nil {
+		return err
+	}
+	like, err := c.Create(context.Background(), &pb.Like{RefType: refType, Liked: !*dislike, Reaction: *reaction, Value: int32(*value), UserId: *user})
+	if err != nil {
+		return err
+	}
+	return printMessages(os.Stdout, like)
+}
+
+func runToggle(c *client.Client, args []string) error {
+	fs := flag.NewFlagSet("toggle", flag.ContinueOnError)
+	target := fs.String("target", "toggle", "The state to reach: toggle, liked, disliked or cleared")
+	user := fs.String("user", "", "The id of the user who toggles the like")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	refType, err := refTypeArgs(fs.Args())
+	if err != nil {
+		return err
+	}
+	if *user == "" {
+		return usageError("-user is required")
+	}
+	value, ok := pb.ToggleLikeRequest_Target_value[strings.ToUpper(*target)]
+	if !ok {
+		return usageError(fmt.Sprintf("unknown target %q", *target))
+	}
+	resp, err := c.Toggle(context.Background(), *user, refType, pb.ToggleLikeRequest_Target(value))
+	if err != nil {
+		return err
+	}
+	return printMessages(os.Stdout, resp)
+}
+
+func runUnlike(c *client.Client, args []string) error {
+	fs := flag.NewFlagSet("unlike", flag.ContinueOnError)
+	version := fs.Uint64("version", 0, "Only delete the like if it is at this version")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	id, err := idArg(fs.Args())
+	if err != nil {
+		return err
+	}
+	like, err := c.DeleteIfVersion(context.Background(), id, *version)
+	if err != nil {
+		return err
+	}
+	return printMessages(os.Stdout, like)
+}
+
+func runUndelete(c *client.Client, args []string) error {
+	id, err := idArg(args)
+	if err != nil {
+		return err
+	}
+	like, err := c.Undelete(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	return printMessages(os.Stdout, like)
+}
+
+func runUpdate(c *client.Client, args []string) error {
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	dislike := fs.Bool("dislike", false, "Change the like to a dislike instead of a like")
+	version := fs.Uint64("version", 0, "Only update the like if it is at this version")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	id, err := idArg(fs.Args())
+	if err != nil {
+		return err
+	}
+	like, err := c.Update(context.Background(), &pb.Like{Id: id, Liked: !*dislike}, *version)
+	if err != nil {
+		return err
+	}
+	return printMessages(os.Stdout, like)
+}
+
+func runWatch(c *client.Client, args []string) error {
+	var refType *pb.RefType
+	if len(args) > 0 {
+		var err error
+		if refType, err = refTypeArgs(args); err != nil {
+			return err
 		}
-		creds, err := credentials.NewClientTLSFromFile(*caFile, *serverHostOverride)
+	}
+	return c.Watch(context.Background(), refType, func(event *pb.LikeEvent) error {
+		return printMessages(os.Stdout, event)
+	})
+}
+
+func runExport(c *client.Client, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	formatName := fs.String("format", "json", "The export format: json, jsonl or csv")
+	file := fs.String("file", "", "The file to write the export to, else stdout")
+	if err := fs.Parse(args); err != nil {
+		return usageError(err.Error())
+	}
+	format, ok := pb.ExportRequest_Format_value[strings.ToUpper(*formatName)]
+	if !ok {
+		return usageError("unknown export format: " + *formatName)
+	}
+	w := os.Stdout
+	if *file != "" {
+		f, err := os.Create(*file)
 		if err != nil {
-			log.Fatalf("Failed to create TLS credentials %v", err)
+			return status.Error(codes.Unknown, fmt.Sprintf("failed to create %s: %v", *file, err))
 		}
-		opts = append(opts, grpc.WithTransportCredentials(creds))
-	} else {
-		opts = append(opts, grpc.WithInsecure())
+		defer f.Close()
+		w = f
 	}
-	host_port := fmt.Sprintf("%s:%d", *host, *port)
-	conn, err := grpc.Dial(host_port, opts...)
-	if err != nil {
-		log.Fatalf("fail to dial: %v", err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	return c.Export(ctx, pb.ExportRequest_Format(format), w)
+}
+
+// printMessages writes the messages to w in the -output format.
+func printMessages(w io.Writer, msgs ...proto.Message) error {
+	switch *output {
+	case "json":
+		m := jsonpb.Marshaler{OrigName: true}
+		for _, msg := range msgs {
+			s, err := m.MarshalToString(msg)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(w, s)
+		}
+	case "text":
+		for i, msg := range msgs {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			proto.MarshalText(w, msg)
+		}
+	default:
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		for i, msg := range msgs {
+			header, row := tableRow(msg)
+			if i == 0 {
+				fmt.Fprintln(tw, header)
+			}
+			fmt.Fprintln(tw, row)
+		}
+		return tw.Flush()
+	}
+	return nil
+}
+
+// tableRow returns the tab separated table header and row of a message.
+func tableRow(msg proto.Message) (string, string) {
+	switch m := msg.(type) {
+	case *pb.Like:
+		return "ID\tREF TYPE\tUSER\tREACTION\tCREATED AT\tDELETED AT\tVERSION",
+			fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%d", m.Id, refTypeString(m.RefType), m.UserId, reactionString(m), timestampString(m.CreatedAt), timestampString(m.DeletedAt), m.Version)
+	case *pb.LikeEvent:
+		like := m.Like
+		if like == nil {
+			like = &pb.Like{}
+		}
+		return "EVENT\tID\tREF TYPE\tREACTION\tTIME",
+			fmt.Sprintf("%s\t%s\t%s\t%s\t%s", m.Type, like.Id, refTypeString(like.RefType), reactionString(like), ptypes.TimestampString(m.Time))
+	case *pb.ToggleLikeResponse:
+		like, summary := m.Like, m.Summary
+		if like == nil {
+			like = &pb.Like{}
+		}
+		if summary == nil {
+			summary = &pb.LikesSummary{}
+		}
+		return "ID\tREF TYPE\tUSER\tREACTION\tDELETED AT\tVERSION\tLIKES\tDISLIKES\tTOTAL",
+			fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d", like.Id, refTypeString(summary.RefType), like.UserId, reactionString(like), timestampString(like.DeletedAt), like.Version, summary.LikeCount, summary.DislikeCount, summary.Total)
+	case *pb.RefTypeParent:
+		return "REF TYPE\tPARENT", fmt.Sprintf("%s\t%s", refTypeString(m.RefType), refTypeString(m.Parent))
+	case *pb.AuditRecord:
+		code := m.Code
+		if m.Replayed {
+			code += " (replayed)"
+		}
+		return "SEQUENCE\tTIME\tPRINCIPAL\tTENANT\tMETHOD\tCODE\tCHANGES\tREQUEST ID",
+			fmt.Sprintf("%d\t%s\t%s\t%s\t%s\t%s\t%d\t%s", m.Sequence, timestampString(m.Time), m.Principal, m.Tenant, m.Method, code, len(m.Changes), m.RequestId)
+	case *pb.LoggingConfig:
+		return "LEVEL\tFORMAT", fmt.Sprintf("%s\t%s", m.Level, m.Format)
+	case *pb.TenantStats:
+		return "TENANT\tLIKES\tDELETED\tREF TYPES\tREQUESTS\tERRORS",
+			fmt.Sprintf("%s\t%d\t%d\t%d\t%d\t%d", m.Tenant, m.Likes, m.DeletedLikes, m.RefTypes, m.Requests, m.Errors)
+	case *pb.LikesSummary:
+		return "REF TYPE\tLIKES\tDISLIKES\tTOTAL\tRATIO\tSCORE\tRATING\tREACTIONS\tDELETED",
+			fmt.Sprintf("%s\t%d\t%d\t%d\t%.3f\t%.3f\t%.2f (%d)\t%s\t%d", refTypeString(m.RefType), m.LikeCount, m.DislikeCount, m.Total, m.LikeRatio, m.Score, m.AverageRating, m.RatingCount, reactionCountsString(m.ReactionCounts), m.DeletedCount)
+	default:
+		return "MESSAGE", proto.CompactTextString(msg)
 	}
-	defer conn.Close()
-	client := pb.NewBeerLikesClient(conn)
+}
 
-	md := metadata.Pairs(
-		"key", "string value",
-		"key-bin", string([]byte{96, 102}), // this binary data will be encoded (base64) before sending
-		// and will be decoded after being transferred.
-	)
-	log.Printf("metadata: %v", md)
-	printLike(client, &pb.LikeQuery{Id: "3e8f9d58-4148-4809-9392-63e90fbc8280"})
+func refTypeString(refType *pb.RefType) string {
+	if refType == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", refType.Name, refType.Id)
+}
 
-	// Like NotFound
-	printLike(client, &pb.LikeQuery{Id: "123-abc"})
+// reactionString returns the reaction of a like and its value, if it has one.
+func reactionString(like *pb.Like) string {
+	reaction := like.Reaction
+	if reaction == "" {
+		reaction = "dislike"
+		if like.Liked {
+			reaction = "like"
+		}
+	}
+	if like.Value != 0 {
+		return fmt.Sprintf("%s %d", reaction, like.Value)
+	}
+	return reaction
+}
 
-	// Like missing.
-	printLike(client, &pb.LikeQuery{})
+// reactionCountsString returns the reaction counts sorted by reaction.
+func reactionCountsString(counts map[string]int32) string {
+	reactions := make([]string, 0, len(counts))
+	for reaction := range counts {
+		reactions = append(reactions, reaction)
+	}
+	sort.Strings(reactions)
+	for i, reaction := range reactions {
+		reactions[i] = fmt.Sprintf("%s=%d", reaction, counts[reaction])
+	}
+	return strings.Join(reactions, " ")
+}
 
-	// return all the likes for a given reftype
-	printLikes(client, &pb.LikesQuery{
-		RefType: &pb.RefType{Name: "beer", Id: "1"},
-	})
+func timestampString(ts *timestamp.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return ptypes.TimestampString(ts)
+}
 
-	// return all the likes for a given reftype
-	printLikesSummary(client, &pb.LikesQuery{
-		RefType: &pb.RefType{Name: "beer", Id: "1"},
-	})
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s [flags] <command> [args]\n\ncommands:\n", os.Args[0])
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[name].usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nflags:\n")
+	flag.PrintDefaults()
+}
 
-	// return all the likes for an incorrect reftype
-	printLikes(client, &pb.LikesQuery{
-		RefType: &pb.RefType{Name: "beer", Id: "xyz"},
-	})
+func dial() (*client.Client, error) {
+	opts := []client.Option{client.WithTimeout(*timeout)}
+	if *tenant != "" {
+		opts = append(opts, client.WithTenant(*tenant))
+	}
+	if *principal != "" {
+		opts = append(opts, client.WithPrincipal(*principal))
+	}
+	if *serviceConfig != "" {
+		var config *client.ServiceConfig
+		var err error
+		if strings.HasPrefix(strings.TrimSpace(*serviceConfig), "{") {
+			config, err = client.ParseServiceConfig([]byte(*serviceConfig))
+		} else {
+			config, err = client.LoadServiceConfig(*serviceConfig)
+		}
+		if err != nil {
+			return nil, usageError(err.Error())
+		}
+		opts = append(opts, client.WithServiceConfig(config))
+	}
+	if *tls {
+		if *caFile == "" {
+			*caFile = testdata.Path("ca.pem")
+		}
+		opts = append(opts, client.WithTLS(*caFile, *serverHostOverride))
+	}
+	if *certFile != "" {
+		opts = append(opts, client.WithClientCert(*certFile, *keyFile))
+	}
+	host_port := fmt.Sprintf("%s:%d", *host, *port)
+	return client.Dial(host_port, opts...)
+}
 
+// Main
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		usage()
+		os.Exit(int(codes.InvalidArgument))
+	}
+	switch *output {
+	case "table", "json", "text":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown output format: %s\n", *output)
+		os.Exit(int(codes.InvalidArgument))
+	}
+	c, err := dial()
+	if _, ok := err.(usageError); ok {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(int(codes.InvalidArgument))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fail to dial: %v\n", err)
+		os.Exit(int(codes.Unavailable))
+	}
+	err = cmd.run(c, flag.Args()[1:])
+	c.Close()
+	if e, ok := err.(usageError); ok {
+		fmt.Fprintf(os.Stderr, "%s\nusage: %s [flags] %s\n", e, os.Args[0], cmd.usage)
+		os.Exit(int(codes.InvalidArgument))
+	}
+	if err != nil {
+		s := status.Convert(err)
+		if id := client.RequestID(err); id != "" {
+			fmt.Fprintf(os.Stderr, "%s: %s (request id %s)\n", s.Code(), s.Message(), id)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", s.Code(), s.Message())
+		}
+		os.Exit(int(s.Code()))
+	}
 }
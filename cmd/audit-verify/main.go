@@ -0,0 +1,51 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+// Command audit-verify checks the hash chain of an audit log written by the
+// file audit sink of the beer likes server.
+//
+//	audit-verify audit.log
+//
+// It exits with 1 and the line of the first broken link if a record was
+// changed, removed or inserted. Records removed from the end of the log leave
+// a valid chain, so compare the last sequence and hash it prints with a copy
+// kept elsewhere to detect them.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/phriscage/beer-likes/beerlikes/audit"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s <audit log>\n", os.Args[0])
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	file, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer file.Close()
+	n, last, err := audit.Verify(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", flag.Arg(0), err)
+		os.Exit(1)
+	}
+	if last == nil {
+		fmt.Printf("%s: no records\n", flag.Arg(0))
+		return
+	}
+	fmt.Printf("%s: %d records verified, the last is %d with hash %s\n", flag.Arg(0), n, last.Sequence, last.Hash)
+}
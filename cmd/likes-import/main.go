@@ -0,0 +1,417 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+// Command likes-import bulk loads likes from a CSV or JSON Lines file into the
+// beer likes service through the ImportLikes RPC.
+//
+//	likes-import [flags] likes.csv
+//
+// CSV files start with a header row. The columns are matched to the like
+// fields by name, as written by the client export subcommand, and -columns
+// maps differently named columns, e.g. -columns id=like_id,liked=thumbs_up.
+// JSON Lines files hold one like per line in the -json_db_file format.
+//
+// Every row is validated before it is sent. Invalid rows are skipped and
+// written to the -error_report file, if given. An import that was interrupted
+// can be continued with -resume_line; importing a like again replaces it, so
+// resuming from an earlier line is safe. Rows without an id are given one
+// derived from -id_namespace, their line and their fields, so they keep the
+// same id when they are imported again.
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/testdata"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+	"github.com/phriscage/beer-likes/beerlikes/client"
+	"github.com/phriscage/beer-likes/beerlikes/reaction"
+)
+
+var (
+	tls                = flag.Bool("tls", false, "Connection uses TLS if true, else plain TCP")
+	caFile             = flag.String("ca_file", "", "The file containning the CA root cert file")
+	certFile           = flag.String("cert_file", "", "The client certificate file, whose common name the server takes as the principal of the calls")
+	keyFile            = flag.String("key_file", "", "The key file of -cert_file")
+	port               = flag.Int("port", 10000, "The server port")
+	host               = flag.String("host", "127.0.0.1", "The server host ip")
+	serverHostOverride = flag.String("server_host_override", "x.test.youtube.com", "The server name use to verify the hostname returned by TLS handshake")
+	tenant             = flag.String("tenant", "", "The tenant to import the likes into, empty uses the default tenant")
+
+	format      = flag.String("format", "", "The input format: csv or jsonl, else taken from the file extension")
+	columns     = flag.String("columns", "", "Comma separated field=column pairs mapping CSV columns to like fields")
+	dryRun      = flag.Bool("dry_run", false, "Validate the input without importing it")
+	errorReport = flag.String("error_report", "", "A CSV file to write the line and error of every rejected row to")
+	resumeLine  = flag.Int("resume_line", 1, "The first line of the input to import")
+	idNamespace = flag.String("id_namespace", "", "Scopes the ids given to rows without one, empty uses the input file name")
+)
+
+// fields are the like fields a CSV column can be mapped to.
+var fields = []string{"id", "ref_type_name", "ref_type_id", "liked", "created_at", "deleted_at", "user_id", "reaction", "value"}
+
+// row is a like read from the input and its line number.
+type row struct {
+	line int
+	like *pb.Like
+	err  error
+}
+
+// reader returns the rows of an input file one at a time.
+type reader interface {
+	Read() (*row, error)
+}
+
+// csvReader reads likes from a CSV file with a header row.
+type csvReader struct {
+	r       *csv.Reader
+	line    int
+	columns map[string]int // column index of each mapped field
+}
+
+func newCSVReader(r io.Reader, mapping map[string]string) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the header: %v", err)
+	}
+	index := make(map[string]int)
+	for i, name := range header {
+		index[strings.TrimSpace(name)] = i
+	}
+	c := &csvReader{r: cr, line: 1, columns: make(map[string]int)}
+	for _, field := range fields {
+		name := field
+		if column, ok := mapping[field]; ok {
+			name = column
+		}
+		if i, ok := index[name]; ok {
+			c.columns[field] = i
+		}
+	}
+	for _, field := range []string{"ref_type_name", "ref_type_id"} {
+		if _, ok := c.columns[field]; !ok {
+			return nil, fmt.Errorf("no column for %s", field)
+		}
+	}
+	return c, nil
+}
+
+func (c *csvReader) Read() (*row, error) {
+	record, err := c.r.Read()
+	if err == io.EOF {
+		return nil, err
+	}
+	c.line++
+	r := &row{line: c.line}
+	if err != nil {
+		r.err = err
+		return r, nil
+	}
+	value := func(field string) string {
+		if i, ok := c.columns[field]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	like := &pb.Like{
+		Id:       value("id"),
+		RefType:  &pb.RefType{Name: value("ref_type_name"), Id: value("ref_type_id")},
+		UserId:   value("user_id"),
+		Reaction: value("reaction"),
+	}
+	if s := value("liked"); s != "" {
+		if like.Liked, err = strconv.ParseBool(s); err != nil {
+			r.err = fmt.Errorf("liked %q is not a boolean", s)
+			return r, nil
+		}
+	}
+	if s := value("value"); s != "" {
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			r.err = fmt.Errorf("value %q is not an integer", s)
+			return r, nil
+		}
+		like.Value = int32(v)
+	}
+	times := []struct {
+		field string
+		ts    **timestamp.Timestamp
+	}{{"created_at", &like.CreatedAt}, {"deleted_at", &like.DeletedAt}}
+	for _, f := range times {
+		if s := value(f.field); s != "" {
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err == nil {
+				*f.ts, err = ptypes.TimestampProto(t)
+			}
+			if err != nil {
+				r.err = fmt.Errorf("%s %q is not an RFC 3339 time", f.field, s)
+				return r, nil
+			}
+		}
+	}
+	r.like = like
+	return r, nil
+}
+
+// jsonLinesReader reads one JSON like per line.
+type jsonLinesReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+func newJSONLinesReader(r io.Reader) *jsonLinesReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64<<10), 4<<20)
+	return &jsonLinesReader{s: s}
+}
+
+func (j *jsonLinesReader) Read() (*row, error) {
+	for j.s.Scan() {
+		j.line++
+		text := strings.TrimSpace(j.s.Text())
+		if text == "" {
+			continue
+		}
+		r := &row{line: j.line, like: &pb.Like{}}
+		if err := jsonpb.UnmarshalString(text, r.like); err != nil {
+			r.like, r.err = nil, err
+		}
+		return r, nil
+	}
+	if err := j.s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// validate checks a like with the rules of the server.
+func validate(like *pb.Like) error {
+	if like.RefType == nil || like.RefType.Name == "" || like.RefType.Id == "" {
+		return errors.New("ref_type name and id are required")
+	}
+	return reaction.Normalize(proto.Clone(like).(*pb.Like))
+}
+
+// rowID returns the id of a row without one: a name-based UUID of the
+// namespace, the line and the fields of the like. Likes have no map fields, so
+// their marshaled bytes are stable.
+func rowID(namespace string, line int, like *pb.Like) (string, error) {
+	data, err := proto.Marshal(like)
+	if err != nil {
+		return "", err
+	}
+	h := sha1.New()
+	fmt.Fprintf(h, "%s\x00%d\x00", namespace, line)
+	h.Write(data)
+	b := h.Sum(nil)[:16]
+	b[6] = b[6]&0x0f | 0x50
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// parseColumns parses the -columns flag.
+func parseColumns(s string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	if s == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%q is not a field=column pair", pair)
+		}
+		field := strings.TrimSpace(parts[0])
+		known := false
+		for _, f := range fields {
+			known = known || f == field
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown field %q, expected one of %s", field, strings.Join(fields, ", "))
+		}
+		mapping[field] = strings.TrimSpace(parts[1])
+	}
+	return mapping, nil
+}
+
+// errorWriter records the rejected rows.
+type errorWriter struct {
+	w     *csv.Writer
+	count int
+}
+
+func (e *errorWriter) add(line int, err error) {
+	e.count++
+	log.Printf("line %d: %v", line, err)
+	if e.w != nil {
+		e.w.Write([]string{strconv.Itoa(line), err.Error()})
+	}
+}
+
+func (e *errorWriter) flush() {
+	if e.w != nil {
+		e.w.Flush()
+	}
+}
+
+func dial() *client.Client {
+	var opts []client.Option
+	if *tenant != "" {
+		opts = append(opts, client.WithTenant(*tenant))
+	}
+	if *tls {
+		if *caFile == "" {
+			*caFile = testdata.Path("ca.pem")
+		}
+		opts = append(opts, client.WithTLS(*caFile, *serverHostOverride))
+	}
+	if *certFile != "" {
+		opts = append(opts, client.WithClientCert(*certFile, *keyFile))
+	}
+	host_port := fmt.Sprintf("%s:%d", *host, *port)
+	c, err := client.Dial(host_port, opts...)
+	if err != nil {
+		log.Fatalf("fail to dial: %v", err)
+	}
+	return c
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatalf("usage: likes-import [flags] file")
+	}
+	path := flag.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	if *idNamespace == "" {
+		*idNamespace = filepath.Base(path)
+	}
+	mapping, err := parseColumns(*columns)
+	if err != nil {
+		log.Fatalf("invalid -columns: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+	var rows reader
+	switch strings.ToLower(*format) {
+	case "csv":
+		if rows, err = newCSVReader(f, mapping); err != nil {
+			log.Fatalf("%s: %v", path, err)
+		}
+	case "jsonl":
+		rows = newJSONLinesReader(f)
+	default:
+		log.Fatalf("unknown input format %q, use -format csv or jsonl", *format)
+	}
+
+	rejected := &errorWriter{}
+	if *errorReport != "" {
+		ef, err := os.Create(*errorReport)
+		if err != nil {
+			log.Fatalf("failed to create %s: %v", *errorReport, err)
+		}
+		defer ef.Close()
+		rejected.w = csv.NewWriter(ef)
+		rejected.w.Write([]string{"line", "error"})
+	}
+
+	var stream pb.BeerLikesAdmin_ImportLikesClient
+	if !*dryRun {
+		c := dial()
+		defer c.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream, err = pb.NewBeerLikesAdminClient(c.Conn()).ImportLikes(ctx)
+		if err != nil {
+			log.Fatalf("ImportLikes(_) = _, %v", err)
+		}
+	}
+
+	// lines maps the index of every like sent to its line in the input.
+	var lines []int
+	lastLine := 0
+	// The server does not acknowledge each like, so after a failure the whole
+	// run is sent again. Every row is sent with an id and imports replace
+	// likes, so the rows the server already stored are not duplicated.
+	failed := func(err error) {
+		rejected.flush()
+		log.Fatalf("import failed, send the run again with -resume_line %d: %v", *resumeLine, err)
+	}
+	for {
+		r, err := rows.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			rejected.flush()
+			log.Fatalf("failed to read %s after line %d: %v", path, lastLine, err)
+		}
+		if r.line < *resumeLine {
+			continue
+		}
+		if r.err == nil {
+			r.err = validate(r.like)
+		}
+		if r.err == nil && r.like.Id == "" {
+			r.like.Id, r.err = rowID(*idNamespace, r.line, r.like)
+		}
+		if r.err != nil {
+			rejected.add(r.line, r.err)
+			continue
+		}
+		if stream != nil {
+			if err := stream.Send(r.like); err != nil {
+				_, err = stream.CloseAndRecv()
+				failed(err)
+			}
+		}
+		lines = append(lines, r.line)
+		lastLine = r.line
+	}
+
+	if stream == nil {
+		log.Printf("dry run: %d valid, %d rejected", len(lines), rejected.count)
+	} else {
+		summary, err := stream.CloseAndRecv()
+		if err != nil {
+			failed(err)
+		}
+		for _, e := range summary.Errors {
+			if int(e.Index) < len(lines) {
+				rejected.add(lines[e.Index], errors.New(e.Message))
+			}
+		}
+		log.Printf("imported %d, rejected %d in %v", summary.Imported, rejected.count, time.Duration(summary.ElapsedTime))
+	}
+	rejected.flush()
+	if rejected.count > 0 {
+		os.Exit(1)
+	}
+}
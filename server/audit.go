@@ -0,0 +1,379 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+	"github.com/phriscage/beer-likes/beerlikes/audit"
+)
+
+// principalHeader is the request metadata key of the principal of a call
+// without a client certificate.
+const principalHeader = "x-principal"
+
+// auditedMethods are the write methods that get an audit record.
+var auditedMethods = map[string]bool{
+	"/beerlikes.BeerLikes/CreateLike":            true,
+	"/beerlikes.BeerLikes/DeleteLike":            true,
+	"/beerlikes.BeerLikes/UndeleteLike":          true,
+	"/beerlikes.BeerLikes/UpdateLike":            true,
+	"/beerlikes.BeerLikes/ToggleLike":            true,
+	"/beerlikes.BeerLikesAdmin/ImportLikes":      true,
+	"/beerlikes.BeerLikesAdmin/SetRefTypeParent": true,
+	"/beerlikes.BeerLikesAdmin/SetLogging":       true,
+}
+
+// auditSink writes audit records somewhere.
+type auditSink interface {
+	write(record *pb.AuditRecord) error
+}
+
+// auditor numbers the audit records and writes them to the sinks.
+type auditor struct {
+	mu       sync.Mutex // protects sequence and orders the writes
+	sequence uint64
+	sinks    []auditSink
+	stream   *auditStreamSink // nil unless the stream sink is enabled
+}
+
+// newAuditor returns an auditor with a comma separated list of sinks: file,
+// stdout and stream. It returns nil if there are none.
+func newAuditor(sinks, path string) (*auditor, error) {
+	a := &auditor{}
+	for _, name := range strings.Split(sinks, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "file":
+			sink, sequence, err := openAuditFile(path)
+			if err != nil {
+				return nil, err
+			}
+			a.sinks = append(a.sinks, sink)
+			a.sequence = sequence
+		case "stdout":
+			a.sinks = append(a.sinks, &auditStdoutSink{})
+		case "stream":
+			a.stream = &auditStreamSink{subs: make(map[int]*auditSubscriber)}
+			a.sinks = append(a.sinks, a.stream)
+		default:
+			return nil, fmt.Errorf("audit sinks must be file, stdout or stream: %q", name)
+		}
+	}
+	if len(a.sinks) == 0 {
+		return nil, nil
+	}
+	return a, nil
+}
+
+// emit numbers a record and writes it to every sink.
+func (a *auditor) emit(record *pb.AuditRecord) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sequence++
+	record.Sequence = a.sequence
+	record.Time = ptypes.TimestampNow()
+	for _, sink := range a.sinks {
+		if err := sink.write(record); err != nil {
+			log.Errorf("Failed to write audit record %d: %v", record.Sequence, err)
+		}
+	}
+}
+
+// auditChanges collects the changes of a write.
+type auditChanges struct {
+	mu       sync.Mutex
+	changes  []*pb.AuditChange
+	replayed bool // the response of an earlier write was returned
+}
+
+type auditChangesKey struct{}
+
+// withAuditChanges returns a context that collects the changes recorded with it.
+func withAuditChanges(ctx context.Context, changes *auditChanges) context.Context {
+	return context.WithValue(ctx, auditChangesKey{}, changes)
+}
+
+// auditChangesFrom returns the changes collected for a context, or nil.
+func auditChangesFrom(ctx context.Context) *auditChanges {
+	changes, _ := ctx.Value(auditChangesKey{}).(*auditChanges)
+	return changes
+}
+
+func (c *auditChanges) add(change *pb.AuditChange) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.changes = append(c.changes, change)
+}
+
+// replay marks the write as a replay of an earlier write with its idempotency key.
+func (c *auditChanges) replay() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.replayed = true
+}
+
+func (c *auditChanges) isReplayed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.replayed
+}
+
+func (c *auditChanges) list() []*pb.AuditChange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.changes
+}
+
+// auditBefore returns the change of an event with the like or parent it
+// replaces. Callers must hold s.mu.
+func (s *beerLikesServer) auditBefore(event *pb.LikeEvent) *pb.AuditChange {
+	if event.Type == pb.LikeEvent_PARENT {
+		return &pb.AuditChange{ParentBefore: s.refTypes.parents[refTypeKey(event.Parent.GetRefType())]}
+	}
+	change := &pb.AuditChange{}
+	change.Before = s.findLike(event.Like.GetId())
+	return change
+}
+
+// auditAfter completes the change of an event with the like or parent it stored.
+func auditAfter(change *pb.AuditChange, event *pb.LikeEvent) {
+	switch event.Type {
+	case pb.LikeEvent_PARENT:
+		change.ParentAfter = event.Parent
+	case pb.LikeEvent_PURGE:
+	default:
+		change.After = event.Like
+	}
+}
+
+// verifiedPrincipal returns the common name of the verified client
+// certificate of a call, or "" if it has none.
+func verifiedPrincipal(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 && len(info.State.VerifiedChains[0]) > 0 {
+			return info.State.VerifiedChains[0][0].Subject.CommonName
+		}
+	}
+	return ""
+}
+
+// principal returns who made a call: the common name of its verified client
+// certificate, its principalHeader or "anonymous". Only the certificate is
+// authenticated; the header is only recorded.
+func principal(ctx context.Context) string {
+	if name := verifiedPrincipal(ctx); name != "" {
+		return name
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(principalHeader); len(values) > 0 && values[0] != "" {
+		return values[0]
+	}
+	return "anonymous"
+}
+
+// audit runs a write with a collector of its changes and emits its record.
+func (a *auditor) audit(ctx context.Context, fullMethod string, call func(ctx context.Context) error) error {
+	changes := &auditChanges{}
+	record := &pb.AuditRecord{
+		Principal: principal(ctx),
+		Tenant:    tenantName(ctx),
+		Method:    fullMethod[strings.LastIndex(fullMethod, "/")+1:],
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		record.Peer = p.Addr.String()
+	}
+	if id, ok := grpc_ctxtags.Extract(ctx).Values()["request_id"].(string); ok {
+		record.RequestId = id
+	}
+	err := call(withAuditChanges(ctx, changes))
+	st := status.Convert(err)
+	record.Code = st.Code().String()
+	if err != nil {
+		record.Error = st.Message()
+	}
+	record.Changes = changes.list()
+	record.Replayed = changes.isReplayed()
+	a.emit(record)
+	return err
+}
+
+func (a *auditor) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !auditedMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+	var resp interface{}
+	err := a.audit(ctx, info.FullMethod, func(ctx context.Context) (err error) {
+		resp, err = handler(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+func (a *auditor) streamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !auditedMethods[info.FullMethod] {
+		return handler(srv, stream)
+	}
+	return a.audit(stream.Context(), info.FullMethod, func(ctx context.Context) error {
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = ctx
+		return handler(srv, wrapped)
+	})
+}
+
+// auditFileSink is the file audit sink. Each record is chained to the one
+// before it by its previous_hash and hash, see package audit.
+type auditFileSink struct {
+	file *os.File
+	hash string // of the last record
+}
+
+// openAuditFile verifies the hash chain of an audit log and opens it to
+// append to. It returns the sequence of the last record.
+func openAuditFile(path string) (*auditFileSink, uint64, error) {
+	if path == "" {
+		return nil, 0, fmt.Errorf("the file audit sink needs an audit_file")
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, 0, err
+	}
+	_, last, err := audit.Verify(file)
+	if err != nil {
+		file.Close()
+		return nil, 0, fmt.Errorf("the audit log %s failed verification: %v", path, err)
+	}
+	sink := &auditFileSink{file: file}
+	if last == nil {
+		return sink, 0, nil
+	}
+	sink.hash = last.Hash
+	return sink, last.Sequence, nil
+}
+
+func (f *auditFileSink) write(record *pb.AuditRecord) error {
+	chained := *record
+	chained.PreviousHash = f.hash
+	hash, err := audit.Hash(&chained)
+	if err != nil {
+		return err
+	}
+	chained.Hash = hash
+	line, err := audit.Marshal(&chained)
+	if err != nil {
+		return err
+	}
+	if _, err := f.file.WriteString(line + "\n"); err != nil {
+		return err
+	}
+	f.hash = hash
+	return f.file.Sync()
+}
+
+// auditStdoutSink is the stdout audit sink.
+type auditStdoutSink struct{}
+
+func (auditStdoutSink) write(record *pb.AuditRecord) error {
+	line, err := audit.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, line)
+	return err
+}
+
+// auditSubscriber is a WatchAudit stream of the records of a tenant.
+type auditSubscriber struct {
+	tenant string
+	ch     chan *pb.AuditRecord
+}
+
+// auditStreamSink is the stream audit sink. It fans the records out to the
+// WatchAudit streams like watchers, dropping the streams that fall behind.
+type auditStreamSink struct {
+	mu   sync.Mutex
+	next int
+	subs map[int]*auditSubscriber
+}
+
+func (s *auditStreamSink) subscribe(tenant string) (int, <-chan *pb.AuditRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	sub := &auditSubscriber{tenant: tenant, ch: make(chan *pb.AuditRecord, watchBuffer)}
+	s.subs[s.next] = sub
+	return s.next, sub.ch
+}
+
+func (s *auditStreamSink) unsubscribe(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sub, ok := s.subs[id]; ok {
+		delete(s.subs, id)
+		close(sub.ch)
+	}
+}
+
+func (s *auditStreamSink) write(record *pb.AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, sub := range s.subs {
+		if sub.tenant != record.Tenant {
+			continue
+		}
+		select {
+		case sub.ch <- record:
+		default:
+			delete(s.subs, id)
+			close(sub.ch)
+		}
+	}
+	return nil
+}
+
+// WatchAudit streams the audit records of the tenant of the request. The
+// records of the other tenants are never sent, as they hold their likes.
+func (t *tenantServer) WatchAudit(query *pb.AuditQuery, stream pb.BeerLikesAdmin_WatchAuditServer) error {
+	if t.audit == nil || t.audit.stream == nil {
+		return status.Error(codes.FailedPrecondition, "the stream audit sink is disabled")
+	}
+	ctx := stream.Context()
+	id, records := t.audit.stream.subscribe(tenantName(ctx))
+	defer t.audit.stream.unsubscribe(id)
+	for {
+		select {
+		case record, ok := <-records:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "the audit watcher fell too far behind")
+			}
+			if query.Method != "" && record.Method != query.Method {
+				continue
+			}
+			if err := stream.Send(record); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,228 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// The settings are taken from, in order of precedence: the command line
+// flags, the environment variables, the config file and the flag defaults.
+
+// configEnvPrefix prefixes the environment variable of each flag, e.g.
+// BEER_LIKES_EVENT_LOG_FILE for -event_log_file.
+const configEnvPrefix = "BEER_LIKES_"
+
+// configKeys maps the section.key settings of a config file to their flags.
+var configKeys = map[string]string{
+	"listener.host": "host",
+	"listener.port": "port",
+
+	"tls.enabled":        "tls",
+	"tls.cert_file":      "cert_file",
+	"tls.key_file":       "key_file",
+	"tls.client_ca_file": "client_ca_file",
+
+	"storage.json_db_file":        "json_db_file",
+	"storage.event_log_file":      "event_log_file",
+	"storage.snapshot_interval":   "snapshot_interval",
+	"storage.archive_retention":   "archive_retention",
+	"storage.fsync":               "fsync",
+	"storage.fsync_interval":      "fsync_interval",
+	"storage.tombstone_retention": "tombstone_retention",
+	"storage.compaction_interval": "compaction_interval",
+	"storage.tenants":             "tenants",
+
+	"access.tenant_principals": "tenant_principals",
+	"access.admin_principals":  "admin_principals",
+
+	"logging.level":    "log_level",
+	"logging.format":   "log_format",
+	"logging.sampling": "log_sampling",
+
+	"limits.idempotency_window":     "idempotency_window",
+	"limits.max_recv_msg_size":      "max_recv_msg_size",
+	"limits.max_concurrent_streams": "max_concurrent_streams",
+
+	"audit.sinks": "audit_sinks",
+	"audit.file":  "audit_file",
+
+	"scoring.confidence": "score_confidence",
+}
+
+// envName returns the environment variable of a flag.
+func envName(name string) string {
+	return configEnvPrefix + strings.ToUpper(name)
+}
+
+// loadConfig sets the flags that were not given on the command line from the
+// environment and the config file, validates the settings, applies the
+// logging settings and logs the effective config at debug level.
+func loadConfig() error {
+	sources := make(map[string]string)
+	flag.Visit(func(f *flag.Flag) {
+		sources[f.Name] = "flag"
+	})
+	path := *configFile
+	if _, ok := sources["config"]; !ok {
+		path = os.Getenv(envName("config"))
+	}
+
+	var fileValues map[string]string
+	if path != "" {
+		var err error
+		if fileValues, err = readConfigFile(path); err != nil {
+			return err
+		}
+	}
+	var err error
+	flag.VisitAll(func(f *flag.Flag) {
+		if _, ok := sources[f.Name]; ok || err != nil || f.Name == "config" {
+			return
+		}
+		if value, ok := os.LookupEnv(envName(f.Name)); ok {
+			if err = flag.Set(f.Name, value); err != nil {
+				err = fmt.Errorf("%s: %v", envName(f.Name), err)
+			}
+			sources[f.Name] = envName(f.Name)
+		} else if value, ok := fileValues[f.Name]; ok {
+			if err = flag.Set(f.Name, value); err != nil {
+				err = fmt.Errorf("%s: %v", path, err)
+			}
+			sources[f.Name] = path
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if err := validateConfig(); err != nil {
+		return err
+	}
+
+	if err := setLogging(*logLevel, *logFormat); err != nil {
+		return err
+	}
+	fields := log.Fields{}
+	flag.VisitAll(func(f *flag.Flag) {
+		source, ok := sources[f.Name]
+		if !ok {
+			source = "default"
+		}
+		fields[f.Name] = fmt.Sprintf("%s (%s)", f.Value, source)
+	})
+	log.WithFields(fields).Debug("Effective config")
+	return nil
+}
+
+// readConfigFile reads a YAML config file of sections of settings and
+// returns the values by flag. Lists are joined with commas.
+func readConfigFile(path string) (map[string]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the config file: %v", err)
+	}
+	var sections map[string]map[string]interface{}
+	if err := yaml.UnmarshalStrict(b, &sections); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	values := make(map[string]string)
+	var unknown []string
+	for section, settings := range sections {
+		for key, value := range settings {
+			name, ok := configKeys[section+"."+key]
+			if !ok {
+				unknown = append(unknown, section+"."+key)
+				continue
+			}
+			if items, ok := value.([]interface{}); ok {
+				parts := make([]string, len(items))
+				for i, item := range items {
+					parts[i] = fmt.Sprint(item)
+				}
+				values[name] = strings.Join(parts, ",")
+			} else if value != nil {
+				values[name] = fmt.Sprint(value)
+			}
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%s: unknown settings: %s", path, strings.Join(unknown, ", "))
+	}
+	return values, nil
+}
+
+// validateConfig checks the settings that the flag types do not.
+func validateConfig() error {
+	if *port < 1 || *port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535: %d", *port)
+	}
+	if *confidence <= 0 || *confidence >= 1 {
+		return fmt.Errorf("score_confidence must be between 0 and 1: %v", *confidence)
+	}
+	if *tls && (*certFile == "" || *keyFile == "") {
+		return fmt.Errorf("tls requires cert_file and key_file")
+	}
+	if _, err := parseSyncPolicy(*fsync); err != nil {
+		return err
+	}
+	tenantNames, err := parseTenants(*tenants)
+	if err != nil {
+		return err
+	}
+	if _, err := parseTenantPrincipals(*tenantPrincipals, tenantNames); err != nil {
+		return fmt.Errorf("tenant_principals: %v", err)
+	}
+	if *clientCA != "" && !*tls {
+		return fmt.Errorf("client_ca_file requires tls")
+	}
+	if (*tenantPrincipals != "" || *adminPrincipals != "") && *clientCA == "" {
+		return fmt.Errorf("tenant_principals and admin_principals require client_ca_file to verify the principals")
+	}
+	if _, err := parseLogLevel(*logLevel); err != nil {
+		return err
+	}
+	if *logFormat != "text" && *logFormat != "json" {
+		return fmt.Errorf("log_format must be text or json: %q", *logFormat)
+	}
+	if _, err := parseLogSampling(*logSampling); err != nil {
+		return err
+	}
+	if *archiveRetention < 0 {
+		return fmt.Errorf("archive_retention cannot be negative: %d", *archiveRetention)
+	}
+	if *maxRecvMsgSize <= 0 {
+		return fmt.Errorf("max_recv_msg_size must be positive: %d", *maxRecvMsgSize)
+	}
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"snapshot_interval", *snapshotInterval},
+		{"fsync_interval", *fsyncInterval},
+		{"idempotency_window", *idempotencyWindow},
+		{"tombstone_retention", *tombstoneRetention},
+		{"compaction_interval", *compactionInterval},
+	} {
+		if d.value < 0 {
+			return fmt.Errorf("%s cannot be negative: %v", d.name, d.value)
+		}
+	}
+	if *fsync == string(syncInterval) && *fsyncInterval == 0 {
+		return fmt.Errorf("fsync_interval must be positive with fsync interval")
+	}
+	return nil
+}
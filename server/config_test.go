@@ -0,0 +1,115 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestConfig writes a config file to a new directory and returns its
+// path and the directory to remove.
+func writeTestConfig(t *testing.T, config string) (string, string) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(path, []byte(config), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return path, dir
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	path, dir := writeTestConfig(t, `
+listener:
+  host: 10.0.0.3
+  port: 10003
+storage:
+  json_db_file: file.json
+`)
+	defer os.RemoveAll(dir)
+	// The flags are global, so they are restored for the other tests.
+	saved := make(map[string]string)
+	flag.VisitAll(func(f *flag.Flag) {
+		saved[f.Name] = f.Value.String()
+	})
+	defer func() {
+		for name, value := range saved {
+			flag.Set(name, value)
+		}
+	}()
+	env := map[string]string{
+		envName("config"): path,
+		envName("host"):   "10.0.0.2",
+		envName("port"):   "10002",
+	}
+	for name, value := range env {
+		os.Setenv(name, value)
+		defer os.Unsetenv(name)
+	}
+	if err := flag.Set("host", "10.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := loadConfig(); err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	tests := []struct {
+		name, source, got, want string
+	}{
+		{"host", "the flag", *host, "10.0.0.1"},
+		{"port", "the environment", flag.Lookup("port").Value.String(), "10002"},
+		{"json_db_file", "the config file", *jsonDBFile, "file.json"},
+		{"archive_retention", "the default", flag.Lookup("archive_retention").Value.String(), "3"},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("%s is %s, want %s from %s", test.name, test.got, test.want, test.source)
+		}
+	}
+}
+
+func TestReadConfigFileRejectsUnknownSettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string // part of the error, or empty if the file is valid
+	}{
+		{"known settings", "listener:\n  port: 10001\naudit:\n  sinks: [file, stdout]\n", ""},
+		{"unknown key", "listener:\n  port: 10001\n  prot: 10002\n", "unknown settings: listener.prot"},
+		{"unknown section", "listen:\n  port: 10001\n", "unknown settings: listen.port"},
+		{"duplicate key", "listener:\n  port: 10001\n  port: 10002\n", "already set"},
+		{"setting outside of a section", "port: 10001\n", "cannot unmarshal"},
+	}
+	for _, test := range tests {
+		path, dir := writeTestConfig(t, test.config)
+		values, err := readConfigFile(path)
+		os.RemoveAll(dir)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: readConfigFile returned %v", test.name, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%s: readConfigFile returned %v and %v, want an error with %q", test.name, values, err, test.err)
+		}
+	}
+	path, dir := writeTestConfig(t, "audit:\n  sinks: [file, stdout]\n")
+	defer os.RemoveAll(dir)
+	values, err := readConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := values["audit_sinks"]; got != "file,stdout" {
+		t.Errorf("the audit sinks list is read as %q, want file,stdout", got)
+	}
+}
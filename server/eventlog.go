@@ -0,0 +1,535 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// maxEventSize bounds the length of a record, so a corrupt length prefix is
+// detected instead of allocating an arbitrary amount of memory.
+const maxEventSize = 4 << 20
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// syncPolicy is when the event log is flushed to stable storage.
+type syncPolicy string
+
+const (
+	syncAlways   syncPolicy = "always"   // before every write is acknowledged
+	syncInterval syncPolicy = "interval" // in the background, losing at most one interval
+	syncNever    syncPolicy = "never"    // whenever the operating system decides to
+)
+
+// parseSyncPolicy validates a -fsync flag value.
+func parseSyncPolicy(value string) (syncPolicy, error) {
+	switch policy := syncPolicy(value); policy {
+	case syncAlways, syncInterval, syncNever:
+		return policy, nil
+	}
+	return "", fmt.Errorf("fsync must be always, interval or never: %q", value)
+}
+
+// eventLog is an append-only file of LikeEvent protobufs that doubles as the
+// write-ahead log of the in-memory likes: an event is written, and synced
+// according to the policy, before it is applied.
+//
+// Each record is a uvarint byte length, the CRC-32C of the marshaled event as
+// 4 big-endian bytes, and the marshaled event.
+type eventLog struct {
+	mu       sync.Mutex // protects file, size, dirty and sequence
+	path     string
+	file     *os.File
+	size     int64 // length of the file up to the last complete record
+	dirty    bool  // written since the last sync
+	policy   syncPolicy
+	sequence uint64 // sequence of the last appended event
+}
+
+// openEventLog opens the event log at path for appending, creating it if needed.
+// The sequence continues from the given value. With the interval policy the
+// log is synced in the background at every interval.
+func openEventLog(path string, sequence uint64, policy syncPolicy, interval time.Duration) (*eventLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	l := &eventLog{path: path, file: file, size: info.Size(), policy: policy, sequence: sequence}
+	if policy == syncInterval {
+		go l.syncLoop(interval)
+	}
+	return l, nil
+}
+
+// append assigns the next sequence to the event and writes it to the log.
+// If the write fails the partial record is truncated, so it never hides the
+// records appended after it.
+func (l *eventLog) append(event *pb.LikeEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	event.Sequence = l.sequence + 1
+	n, err := writeEvent(l.file, event)
+	if err == nil && l.policy == syncAlways {
+		err = l.file.Sync()
+	}
+	if err != nil {
+		if n > 0 {
+			if terr := l.file.Truncate(l.size); terr != nil {
+				log.Errorf("Failed to truncate a partial record in %s: %v", l.path, terr)
+			}
+		}
+		return err
+	}
+	l.size += int64(n)
+	l.dirty = l.policy != syncAlways
+	l.sequence = event.Sequence
+	return nil
+}
+
+// sync flushes the log to stable storage if it was written since the last sync.
+func (l *eventLog) sync() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.dirty {
+		return nil
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+	l.dirty = false
+	return nil
+}
+
+// syncLoop syncs the log at every interval.
+func (l *eventLog) syncLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := l.sync(); err != nil {
+			log.Errorf("Failed to sync %s: %v", l.path, err)
+		}
+	}
+}
+
+// rotate archives the current log file as <path>.<sequence> and starts a new,
+// empty one. Callers must make sure the archived events are covered by a snapshot.
+func (l *eventLog) rotate() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(l.path, fmt.Sprintf("%s.%d", l.path, l.sequence)); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	l.file = file
+	l.size = 0
+	l.dirty = false
+	return nil
+}
+
+// pruneArchives deletes the archived log files <path>.<sequence> except the
+// keep with the highest sequences.
+func pruneArchives(path string, keep int) error {
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return err
+	}
+	var sequences []uint64
+	for _, match := range matches {
+		if sequence, err := strconv.ParseUint(strings.TrimPrefix(match, path+"."), 10, 64); err == nil {
+			sequences = append(sequences, sequence)
+		}
+	}
+	if len(sequences) <= keep {
+		return nil
+	}
+	sort.Slice(sequences, func(i, j int) bool { return sequences[i] < sequences[j] })
+	for _, sequence := range sequences[:len(sequences)-keep] {
+		if err := os.Remove(fmt.Sprintf("%s.%d", path, sequence)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lastSequence returns the sequence of the last appended event.
+func (l *eventLog) lastSequence() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.sequence
+}
+
+// writeEvent writes a single record and returns the number of bytes written.
+func writeEvent(w io.Writer, event *pb.LikeEvent) (int, error) {
+	data, err := proto.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+	buf := make([]byte, binary.MaxVarintLen64+4, binary.MaxVarintLen64+4+len(data))
+	n := binary.PutUvarint(buf, uint64(len(data)))
+	binary.BigEndian.PutUint32(buf[n:], crc32.Checksum(data, crcTable))
+	return w.Write(append(buf[:n+4], data...))
+}
+
+// tornRecordError reports a last record that was only partly written, or is
+// corrupt, and the offset of the file where it starts.
+type tornRecordError struct {
+	path   string
+	offset int64
+	err    error
+}
+
+func (e *tornRecordError) Error() string {
+	return fmt.Sprintf("%s: torn record at offset %d: %v", e.path, e.offset, e.err)
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
+
+// readEvents calls fn for every event in the file at path, in order.
+// A missing file has no events. Reading stops with a *tornRecordError at a last
+// record that is incomplete or fails its checksum. A corrupt record followed by
+// others was not torn by a crash, and stops reading with an error instead.
+func readEvents(path string, fn func(*pb.LikeEvent) error) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	r := &countingReader{r: bufio.NewReader(file)}
+	for {
+		offset := r.n
+		torn := func(err error) error {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return &tornRecordError{path: path, offset: offset, err: err}
+		}
+		corrupt := func(err error) error {
+			if _, peek := r.r.Peek(1); peek == io.EOF {
+				return torn(err)
+			}
+			return fmt.Errorf("%s: corrupt record at offset %d: %v", path, offset, err)
+		}
+		size, err := binary.ReadUvarint(r)
+		if err == io.EOF && r.n == offset {
+			return nil
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return torn(err)
+		}
+		if err != nil {
+			return corrupt(err)
+		}
+		if size > maxEventSize {
+			return corrupt(fmt.Errorf("record of %d bytes exceeds %d", size, maxEventSize))
+		}
+		var sum [4]byte
+		if _, err := io.ReadFull(r, sum[:]); err != nil {
+			return torn(err)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return torn(err)
+		}
+		if crc32.Checksum(data, crcTable) != binary.BigEndian.Uint32(sum[:]) {
+			return corrupt(fmt.Errorf("checksum mismatch"))
+		}
+		event := &pb.LikeEvent{}
+		if err := proto.Unmarshal(data, event); err != nil {
+			return corrupt(err)
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+}
+
+// writeSnapshot writes the RefType parents and the likes, deleted or not, as a
+// compacted event log, one PARENT or LIKE event each stamped with the sequence
+// the snapshot covers. The first record has no like and only carries the
+// sequence, so an empty snapshot still records it.
+// The file is replaced atomically so a crash never leaves a partial snapshot.
+func writeSnapshot(path string, sequence uint64, parents []*pb.RefTypeParent, likes []*pb.Like) error {
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	if _, err := writeEvent(w, &pb.LikeEvent{Sequence: sequence}); err != nil {
+		file.Close()
+		return err
+	}
+	for _, parent := range parents {
+		event := &pb.LikeEvent{Type: pb.LikeEvent_PARENT, Sequence: sequence, Parent: parent}
+		if _, err := writeEvent(w, event); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	for _, like := range likes {
+		event := &pb.LikeEvent{Type: pb.LikeEvent_LIKE, Sequence: sequence, Like: like}
+		if _, err := writeEvent(w, event); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// record appends a like event to the event log, applies it and publishes it
+// to the watchers. Callers must hold s.mu.
+func (s *beerLikesServer) record(ctx context.Context, eventType pb.LikeEvent_Type, like *pb.Like) error {
+	return s.recordEvent(ctx, &pb.LikeEvent{Type: eventType, Time: ptypes.TimestampNow(), Like: like})
+}
+
+// recordEvent appends an event to the event log, applies it and publishes it
+// to the watchers. Callers must hold s.mu.
+func (s *beerLikesServer) recordEvent(ctx context.Context, event *pb.LikeEvent) error {
+	if s.events != nil {
+		if err := s.events.append(event); err != nil {
+			return err
+		}
+	}
+	changes := auditChangesFrom(ctx)
+	var change *pb.AuditChange
+	if changes != nil {
+		change = s.auditBefore(event)
+	}
+	s.apply(event)
+	s.watchers.publish(event)
+	if changes != nil {
+		auditAfter(change, event)
+		changes.add(change)
+	}
+	return nil
+}
+
+// apply updates the likes with an event. Deleted likes are kept, and are only
+// removed by a PURGE event. Callers must hold s.mu.
+func (s *beerLikesServer) apply(event *pb.LikeEvent) {
+	if event.Type == pb.LikeEvent_PARENT && event.Parent != nil {
+		s.applyParent(event.Parent)
+		return
+	}
+	if event.Like == nil {
+		return
+	}
+	if current := s.findLike(event.Like.Id); current != nil {
+		s.changed(current.RefType)
+	}
+	if event.Type == pb.LikeEvent_PURGE || event.Type == pb.LikeEvent_UNLIKE && event.Like.DeletedAt == nil {
+		// An UNLIKE without deleted_at was logged before deleted likes were kept.
+		s.unsave(event.Like.Id)
+		return
+	}
+	if event.Like.Version == 0 {
+		// Logged before likes had versions.
+		event.Like.Version = 1
+	}
+	if event.Like.Reaction == "" {
+		// Logged before likes had reactions.
+		event.Like.Reaction = voteReaction(event.Like.Liked)
+	}
+	s.save(event.Like)
+	s.changed(event.Like.RefType)
+}
+
+// replay rebuilds the likes from the snapshot and the event log at logPath and
+// returns the sequence of the last event. Without a snapshot the likes are
+// seeded from the JSON file at seedPath first.
+func (s *beerLikesServer) replay(logPath, seedPath string) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var sequence uint64
+	snapshotPath := logPath + ".snapshot"
+	if _, err := os.Stat(snapshotPath); os.IsNotExist(err) {
+		s.loadLikes(seedPath)
+	} else {
+		err := readEvents(snapshotPath, func(event *pb.LikeEvent) error {
+			sequence = event.Sequence
+			s.apply(event)
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+		log.Infof("Loaded %d likes from snapshot %s at sequence %d", len(s.savedLikes), snapshotPath, sequence)
+	}
+	snapshotted := sequence
+	s.snapshotSequence = snapshotted
+	replayed := 0
+	err := readEvents(logPath, func(event *pb.LikeEvent) error {
+		// Events covered by the snapshot are left behind if the server
+		// stopped between writing the snapshot and rotating the log.
+		if event.Sequence <= snapshotted {
+			return nil
+		}
+		s.apply(event)
+		sequence = event.Sequence
+		replayed++
+		return nil
+	})
+	if torn, ok := err.(*tornRecordError); ok {
+		// The server crashed while appending the last record, which was never
+		// acknowledged. Truncate it so new records are not appended after it.
+		log.Warnf("Truncating the event log: %v", torn)
+		err = os.Truncate(logPath, torn.offset)
+	}
+	log.Infof("Replayed %d events from %s up to sequence %d", replayed, logPath, sequence)
+	return sequence, err
+}
+
+// snapshot writes the current likes to the snapshot file, rotates the event log
+// and deletes the archived logs beyond -archive_retention.
+func (s *beerLikesServer) snapshot() error {
+	// A read lock is enough to keep writers out while the snapshot is taken.
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sequence := s.events.lastSequence()
+	if sequence == s.snapshotSequence {
+		return nil
+	}
+	if err := writeSnapshot(s.events.path+".snapshot", sequence, s.refTypes.list(""), s.savedLikes); err != nil {
+		return err
+	}
+	s.snapshotSequence = sequence
+	log.Debugf("Wrote a snapshot of %d likes at sequence %d", len(s.savedLikes), sequence)
+	if err := s.events.rotate(); err != nil {
+		return err
+	}
+	return pruneArchives(s.events.path, *archiveRetention)
+}
+
+// snapshotLoop snapshots the likes at every interval.
+func (s *beerLikesServer) snapshotLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := s.snapshot(); err != nil {
+			log.Errorf("Failed to snapshot the likes: %v", err)
+		}
+	}
+}
+
+// compact purges the likes that were deleted more than retention ago and
+// returns how many were purged. The purges are audited as one record of the
+// server itself, emitted after the lock is released so a slow sink does not
+// block the calls.
+func (s *beerLikesServer) compact(retention time.Duration) (int, error) {
+	changes := &auditChanges{}
+	n, err := s.purge(withAuditChanges(context.Background(), changes), time.Now().Add(-retention))
+	if s.audit != nil && (n > 0 || err != nil) {
+		record := &pb.AuditRecord{Principal: "system", Tenant: s.tenant, Method: "compaction", Code: codes.OK.String(), Changes: changes.list()}
+		if err != nil {
+			record.Code, record.Error = codes.Internal.String(), err.Error()
+		}
+		s.audit.emit(record)
+	}
+	return n, err
+}
+
+// purge records a PURGE event for every like deleted before cutoff and
+// returns how many were purged.
+func (s *beerLikesServer) purge(ctx context.Context, cutoff time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var tombstones []*pb.Like
+	for _, item := range s.savedLikes {
+		if item.DeletedAt == nil {
+			continue
+		}
+		if deletedAt, err := ptypes.Timestamp(item.DeletedAt); err == nil && deletedAt.Before(cutoff) {
+			tombstones = append(tombstones, item)
+		}
+	}
+	for i, like := range tombstones {
+		if err := s.record(ctx, pb.LikeEvent_PURGE, like); err != nil {
+			return i, err
+		}
+	}
+	return len(tombstones), nil
+}
+
+// compactLoop purges the likes deleted more than retention ago at every interval.
+func (s *beerLikesServer) compactLoop(retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		n, err := s.compact(retention)
+		if err != nil {
+			log.Errorf("Failed to purge the deleted likes: %v", err)
+		}
+		if n > 0 {
+			log.Debugf("Purged %d likes deleted before %v", n, time.Now().Add(-retention).Format(time.RFC3339))
+		}
+	}
+}
@@ -0,0 +1,250 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// newTestServer returns a server without likes, event log or flags.
+func newTestServer() *beerLikesServer {
+	return &beerLikesServer{positions: make(map[string]int), rollups: newRollups(), index: newLikeIndex(), versions: newVersions(), refTypes: newRefTypeTree(), summaries: newSummaryCache(), watchers: newWatchers()}
+}
+
+// writeTestLog appends n LIKE events to a new event log in dir and returns its
+// path and the offset where the last record starts.
+func writeTestLog(t *testing.T, dir string, n int) (string, int64) {
+	path := filepath.Join(dir, "likes.log")
+	l, err := openEventLog(path, 0, syncAlways, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.file.Close()
+	var offset int64
+	for i := 1; i <= n; i++ {
+		offset = l.size
+		like := &pb.Like{Id: fmt.Sprintf("like-%d", i), RefType: &pb.RefType{Name: "beer", Id: "1"}, Liked: true}
+		if err := l.append(&pb.LikeEvent{Type: pb.LikeEvent_LIKE, Like: like}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path, offset
+}
+
+// checkReplay replays the log at path and checks that it keeps the want
+// records before the torn one, truncates the file at offset and accepts new
+// records after the truncation.
+func checkReplay(t *testing.T, path string, offset int64, want int) {
+	s := newTestServer()
+	sequence, err := s.replay(path, filepath.Join(filepath.Dir(path), "missing.json"))
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if sequence != uint64(want) {
+		t.Errorf("replay returned sequence %d, want %d", sequence, want)
+	}
+	if len(s.savedLikes) != want {
+		t.Errorf("replay kept %d likes, want %d", len(s.savedLikes), want)
+	}
+	for i, like := range s.savedLikes {
+		if id := fmt.Sprintf("like-%d", i+1); like.Id != id {
+			t.Errorf("like %d is %s, want %s", i, like.Id, id)
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != offset {
+		t.Errorf("the log is %d bytes after replay, want it truncated to %d", info.Size(), offset)
+	}
+
+	l, err := openEventLog(path, sequence, syncAlways, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.file.Close()
+	like := &pb.Like{Id: "after", RefType: &pb.RefType{Name: "beer", Id: "1"}}
+	if err := l.append(&pb.LikeEvent{Type: pb.LikeEvent_LIKE, Like: like}); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	err = readEvents(path, func(event *pb.LikeEvent) error {
+		ids = append(ids, event.Like.Id)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("readEvents after the truncation: %v", err)
+	}
+	if len(ids) != want+1 || ids[want] != "after" {
+		t.Errorf("read %v after the truncation, want %d likes and then after", ids, want)
+	}
+}
+
+func TestReplayTruncatesRecordCutMidPayload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path, offset := writeTestLog(t, dir, 3)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Cut the last record a few bytes before its end.
+	if err := os.Truncate(path, info.Size()-3); err != nil {
+		t.Fatal(err)
+	}
+	checkReplay(t, path, offset, 2)
+}
+
+func TestReplayTruncatesRecordWithChecksumMismatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path, offset := writeTestLog(t, dir, 3)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Flip a bit of the last payload byte, so only the checksum catches it.
+	data[len(data)-1] ^= 0x01
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = readEvents(path, func(*pb.LikeEvent) error { return nil })
+	if torn, ok := err.(*tornRecordError); !ok || torn.offset != offset {
+		t.Fatalf("readEvents returned %v, want a torn record at offset %d", err, offset)
+	}
+	checkReplay(t, path, offset, 2)
+}
+
+func TestReplayTruncatesLengthCutMidUvarint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path, _ := writeTestLog(t, dir, 2)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offset := info.Size()
+	// A length of 300 takes two uvarint bytes; write only the first.
+	var prefix [binary.MaxVarintLen64]byte
+	if n := binary.PutUvarint(prefix[:], 300); n != 2 {
+		t.Fatalf("300 is a %d byte uvarint, want 2", n)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Write(prefix[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	checkReplay(t, path, offset, 2)
+}
+
+func TestReplayFailsOnCorruptRecordBeforeTheEnd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// The second record starts where the last of two records does.
+	_, second := writeTestLog(t, dir, 2)
+	if err := os.Remove(filepath.Join(dir, "likes.log")); err != nil {
+		t.Fatal(err)
+	}
+	path, third := writeTestLog(t, dir, 3)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Flip a bit of the last payload byte of the second record, which the
+	// third record follows.
+	data[third-1] ^= 0x01
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServer()
+	_, err = s.replay(path, filepath.Join(dir, "missing.json"))
+	if err == nil {
+		t.Fatal("replay of a log with a corrupt record before the end succeeded")
+	}
+	if _, ok := err.(*tornRecordError); ok {
+		t.Fatalf("replay returned the torn record %v, want a corrupt record", err)
+	}
+	if want := fmt.Sprintf("corrupt record at offset %d", second); !strings.Contains(err.Error(), want) {
+		t.Errorf("replay returned %v, want %q", err, want)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != int64(len(data)) {
+		t.Errorf("the log is %d bytes after replay, want it left at %d", info.Size(), len(data))
+	}
+}
+
+func TestPurgeRemovesExpiredTombstones(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer()
+	kept := createTestLike(t, s, &pb.Like{Liked: true})
+	old := createTestLike(t, s, &pb.Like{Liked: true})
+	recent := createTestLike(t, s, &pb.Like{Liked: false})
+	for _, like := range []*pb.Like{old, recent} {
+		if _, err := s.DeleteLike(ctx, &pb.LikeQuery{Id: like.Id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Deleted two days ago, so a retention of one day expires it.
+	s.findLike(old.Id).DeletedAt, _ = ptypes.TimestampProto(time.Now().Add(-48 * time.Hour))
+
+	n, err := s.compact(24 * time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("compact purged %d likes, want 1", n)
+	}
+	if s.findLike(old.Id) != nil {
+		t.Errorf("the expired tombstone %s was not purged", old.Id)
+	}
+	if _, err := s.UndeleteLike(ctx, &pb.LikeQuery{Id: old.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("UndeleteLike of a purged like returned %v, want NotFound", err)
+	}
+	for _, like := range []*pb.Like{kept, recent} {
+		if s.findLike(like.Id) == nil {
+			t.Errorf("compact purged %s, which is not an expired tombstone", like.Id)
+		}
+	}
+	if likes := s.index.refType(kept.RefType); len(likes) != 2 {
+		t.Errorf("the index has %d likes of %v after compact, want 2", len(likes), kept.RefType)
+	}
+}
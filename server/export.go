@@ -0,0 +1,138 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// exportChunkSize is the largest chunk sent by ExportLikes.
+const exportChunkSize = 64 << 10
+
+// csvHeader names the columns of a CSV export.
+var csvHeader = []string{"id", "ref_type_name", "ref_type_id", "liked", "created_at", "deleted_at", "user_id", "reaction", "value"}
+
+// chunkWriter sends everything written to it as ExportChunks.
+type chunkWriter struct {
+	stream pb.BeerLikesAdmin_ExportLikesServer
+}
+
+func (w chunkWriter) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+	if err := w.stream.Send(&pb.ExportChunk{Data: data}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ExportLikes streams a snapshot of all the likes in the requested format.
+// The likes are copied under the read lock, so the export is consistent even
+// while likes are being created and deleted.
+func (s *beerLikesServer) ExportLikes(req *pb.ExportRequest, stream pb.BeerLikesAdmin_ExportLikesServer) error {
+	s.mu.RLock()
+	likes := make([]*pb.Like, len(s.savedLikes))
+	copy(likes, s.savedLikes)
+	s.mu.RUnlock()
+
+	w := bufio.NewWriterSize(chunkWriter{stream}, exportChunkSize)
+	var err error
+	switch req.Format {
+	case pb.ExportRequest_JSON:
+		err = writeJSON(w, likes)
+	case pb.ExportRequest_JSONL:
+		err = writeJSONLines(w, likes)
+	case pb.ExportRequest_CSV:
+		err = writeCSV(w, likes)
+	default:
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("unknown export format: %v", req.Format))
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	return err
+}
+
+// writeJSON writes the likes as a JSON array in the format of the -json_db_file.
+func writeJSON(w io.Writer, likes []*pb.Like) error {
+	m := jsonpb.Marshaler{OrigName: true, Indent: "    "}
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	for i, like := range likes {
+		if i > 0 {
+			if _, err := io.WriteString(w, ", "); err != nil {
+				return err
+			}
+		}
+		if err := m.Marshal(w, like); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
+
+// writeJSONLines writes one JSON like per line.
+func writeJSONLines(w io.Writer, likes []*pb.Like) error {
+	m := jsonpb.Marshaler{OrigName: true}
+	for _, like := range likes {
+		if err := m.Marshal(w, like); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCSV writes a header row followed by one row per like. The created_at
+// and deleted_at columns are RFC 3339 and empty for likes without one.
+func writeCSV(w io.Writer, likes []*pb.Like) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, like := range likes {
+		refType := like.RefType
+		if refType == nil {
+			refType = &pb.RefType{}
+		}
+		row := []string{like.Id, refType.Name, refType.Id, strconv.FormatBool(like.Liked), csvTime(like.CreatedAt), csvTime(like.DeletedAt), like.UserId, like.Reaction, strconv.Itoa(int(like.Value))}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvTime formats a timestamp as RFC 3339, or as an empty string if it is not set.
+func csvTime(ts *timestamp.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
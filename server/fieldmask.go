@@ -0,0 +1,104 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// readPaths are the paths of a like that a read mask can select.
+var readPaths = map[string]bool{
+	"ref_type": true, "ref_type.name": true, "ref_type.id": true,
+	"id": true, "liked": true, "created_at": true, "version": true,
+	"deleted_at": true, "user_id": true, "reaction": true, "value": true,
+}
+
+// updatePaths are the paths of a like that an update mask can select.
+var updatePaths = map[string]bool{"liked": true, "reaction": true, "value": true, "ref_type": true}
+
+// validateMask returns an InvalidArgument error if a mask has a path that is
+// not one of paths.
+func validateMask(name string, mask *field_mask.FieldMask, paths map[string]bool) error {
+	for _, path := range mask.GetPaths() {
+		if !paths[path] {
+			return status.Error(codes.InvalidArgument, fmt.Sprintf("%s has an unknown path: %q", name, path))
+		}
+	}
+	return nil
+}
+
+// hasPath reports whether a mask has a path.
+func hasPath(mask *field_mask.FieldMask, path string) bool {
+	for _, item := range mask.GetPaths() {
+		if item == path {
+			return true
+		}
+	}
+	return false
+}
+
+// maskLike returns a copy of a like with only the fields of a validated read
+// mask, or the like itself if the mask is empty.
+func maskLike(like *pb.Like, mask *field_mask.FieldMask) *pb.Like {
+	if len(mask.GetPaths()) == 0 || like == nil {
+		return like
+	}
+	masked := &pb.Like{}
+	for _, path := range mask.Paths {
+		switch path {
+		case "ref_type":
+			masked.RefType = like.RefType
+		case "ref_type.name", "ref_type.id":
+			if like.RefType == nil || hasPath(mask, "ref_type") {
+				continue
+			}
+			if masked.RefType == nil {
+				masked.RefType = &pb.RefType{}
+			}
+			if path == "ref_type.name" {
+				masked.RefType.Name = like.RefType.Name
+			} else {
+				masked.RefType.Id = like.RefType.Id
+			}
+		case "id":
+			masked.Id = like.Id
+		case "liked":
+			masked.Liked = like.Liked
+		case "created_at":
+			masked.CreatedAt = like.CreatedAt
+		case "version":
+			masked.Version = like.Version
+		case "deleted_at":
+			masked.DeletedAt = like.DeletedAt
+		case "user_id":
+			masked.UserId = like.UserId
+		case "reaction":
+			masked.Reaction = like.Reaction
+		case "value":
+			masked.Value = like.Value
+		}
+	}
+	return masked
+}
+
+// maskLikes returns the likes with only the fields of a validated read mask.
+func maskLikes(likes []*pb.Like, mask *field_mask.FieldMask) []*pb.Like {
+	if len(mask.GetPaths()) == 0 {
+		return likes
+	}
+	masked := make([]*pb.Like, len(likes))
+	for i, like := range likes {
+		masked[i] = maskLike(like, mask)
+	}
+	return masked
+}
@@ -0,0 +1,168 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"golang.org/x/net/context"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+func TestValidateMask(t *testing.T) {
+	tests := []struct {
+		paths []string
+		valid map[string]bool
+		want  codes.Code
+	}{
+		{nil, readPaths, codes.OK},
+		{[]string{"id", "liked", "ref_type.id"}, readPaths, codes.OK},
+		{[]string{"id", "nope"}, readPaths, codes.InvalidArgument},
+		{[]string{"ref_type.nope"}, readPaths, codes.InvalidArgument},
+		{[]string{"liked", "ref_type"}, updatePaths, codes.OK},
+		{[]string{"id"}, updatePaths, codes.InvalidArgument},
+		{[]string{"created_at"}, updatePaths, codes.InvalidArgument},
+	}
+	for _, test := range tests {
+		err := validateMask("mask", &field_mask.FieldMask{Paths: test.paths}, test.valid)
+		if code := status.Code(err); code != test.want {
+			t.Errorf("validateMask(%v) returned %v, want %v", test.paths, err, test.want)
+		}
+	}
+}
+
+func TestMaskLike(t *testing.T) {
+	like := &pb.Like{
+		RefType:   &pb.RefType{Name: "beer", Id: "1"},
+		Id:        "a",
+		Liked:     true,
+		CreatedAt: &timestamp.Timestamp{Seconds: 1},
+		Version:   2,
+		UserId:    "u1",
+		Reaction:  reactionLike,
+	}
+	tests := []struct {
+		paths []string
+		want  *pb.Like
+	}{
+		{nil, like},
+		{[]string{"id", "liked"}, &pb.Like{Id: "a", Liked: true}},
+		{[]string{"ref_type.id"}, &pb.Like{RefType: &pb.RefType{Id: "1"}}},
+		{[]string{"ref_type.name", "ref_type.id"}, &pb.Like{RefType: &pb.RefType{Name: "beer", Id: "1"}}},
+		{[]string{"ref_type", "ref_type.id"}, &pb.Like{RefType: like.RefType}},
+		{[]string{"version", "user_id", "reaction", "value"}, &pb.Like{Version: 2, UserId: "u1", Reaction: reactionLike}},
+	}
+	for _, test := range tests {
+		got := maskLike(like, &field_mask.FieldMask{Paths: test.paths})
+		if !proto.Equal(got, test.want) {
+			t.Errorf("maskLike(%v) = %v, want %v", test.paths, got, test.want)
+		}
+	}
+	if like.Id != "a" || like.RefType.Name != "beer" {
+		t.Errorf("maskLike changed the like to %v", like)
+	}
+}
+
+func TestUpdateMaskOnlyChangesItsFields(t *testing.T) {
+	beer := &pb.RefType{Name: "beer", Id: "1"}
+	other := &pb.RefType{Name: "beer", Id: "2"}
+	tests := []struct {
+		name   string
+		paths  []string
+		update *pb.Like
+		want   *pb.Like
+	}{
+		{"value", []string{"value"}, &pb.Like{Liked: true, Value: 2, RefType: other}, &pb.Like{Reaction: reactionRating, Value: 2, RefType: beer}},
+		{"ref_type", []string{"ref_type"}, &pb.Like{Liked: true, Value: 2, RefType: other}, &pb.Like{Reaction: reactionRating, Value: 4, RefType: other}},
+		{"reaction and value", []string{"reaction", "value"}, &pb.Like{Reaction: "cheers", RefType: other}, &pb.Like{Reaction: "cheers", RefType: beer}},
+		{"liked", []string{"liked"}, &pb.Like{Liked: true, Reaction: "cheers", RefType: other}, &pb.Like{Liked: true, Reaction: reactionLike, RefType: beer}},
+	}
+	for _, test := range tests {
+		s := newTestServer()
+		like := createTestLike(t, s, &pb.Like{RefType: beer, UserId: "u1", Reaction: reactionRating, Value: 4})
+		test.update.Id = like.Id
+		_, err := s.UpdateLike(context.Background(), &pb.UpdateLikeRequest{Like: test.update, UpdateMask: &field_mask.FieldMask{Paths: test.paths}})
+		if err != nil {
+			t.Errorf("%s: UpdateLike returned %v", test.name, err)
+			continue
+		}
+		got := s.findLike(like.Id)
+		if got.Liked != test.want.Liked || got.Reaction != test.want.Reaction || got.Value != test.want.Value || !proto.Equal(got.RefType, test.want.RefType) {
+			t.Errorf("%s: the like is %v, want liked %v, %q, %d and %v", test.name, got, test.want.Liked, test.want.Reaction, test.want.Value, test.want.RefType)
+		}
+		if got.UserId != "u1" || !proto.Equal(got.CreatedAt, like.CreatedAt) {
+			t.Errorf("%s: the update changed the fields outside of its mask: %v", test.name, got)
+		}
+	}
+}
+
+// watchStream passes the events sent by WatchLikes to a channel.
+type watchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *pb.LikeEvent
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchStream) Send(event *pb.LikeEvent) error {
+	s.events <- event
+	return nil
+}
+
+func TestWatchLikesReadMask(t *testing.T) {
+	s := newTestServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &watchStream{ctx: ctx, events: make(chan *pb.LikeEvent, 1)}
+	query := &pb.LikesQuery{RefType: &pb.RefType{Name: "beer", Id: "1"}, ReadMask: &field_mask.FieldMask{Paths: []string{"id", "liked"}}}
+
+	if err := s.WatchLikes(&pb.LikesQuery{ReadMask: &field_mask.FieldMask{Paths: []string{"nope"}}}, stream); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("WatchLikes with an unknown read_mask path returned %v, want InvalidArgument", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.WatchLikes(query, stream)
+	}()
+	// Wait for the watcher to subscribe before the like is created.
+	for {
+		s.watchers.mu.Lock()
+		n := len(s.watchers.subs)
+		s.watchers.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	like := createTestLike(t, s, &pb.Like{Liked: true, UserId: "u1"})
+	select {
+	case event := <-stream.events:
+		if want := (&pb.Like{Id: like.Id, Liked: true}); !proto.Equal(event.Like, want) {
+			t.Errorf("WatchLikes sent the like %v, want %v", event.Like, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchLikes sent no event")
+	}
+	if saved := s.findLike(like.Id); saved.RefType == nil || saved.UserId != "u1" {
+		t.Errorf("masking the event changed the saved like to %v", saved)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("WatchLikes returned %v after the cancel, want %v", err, context.Canceled)
+	}
+}
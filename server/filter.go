@@ -0,0 +1,115 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// validateLikesFilters checks the filters, order and limit of a LikesQuery.
+func validateLikesFilters(query *pb.LikesQuery) error {
+	if _, ok := pb.LikesQuery_LikedFilter_name[int32(query.Liked)]; !ok {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("unknown liked filter: %d", query.Liked))
+	}
+	if _, ok := pb.LikesQuery_OrderBy_name[int32(query.OrderBy)]; !ok {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("unknown order_by: %d", query.OrderBy))
+	}
+	if query.Limit < 0 {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("limit cannot be negative: %d", query.Limit))
+	}
+	if len(query.LikeIds) > maxQueryIds {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("%d like_ids exceeds the limit of %d", len(query.LikeIds), maxQueryIds))
+	}
+	if query.CreatedAfter != nil && query.CreatedBefore != nil && !timestampBefore(query.CreatedAfter, query.CreatedBefore) {
+		return status.Error(codes.InvalidArgument, "created_after must be before created_before")
+	}
+	return nil
+}
+
+// validateUnfiltered rejects the filters, order and limit of a LikesQuery of a
+// call that does not apply them, rather than ignoring them.
+func validateUnfiltered(method string, query *pb.LikesQuery) error {
+	var field string
+	switch {
+	case query.Liked != pb.LikesQuery_ALL:
+		field = "liked"
+	case query.UserId != "":
+		field = "user_id"
+	case len(query.LikeIds) > 0:
+		field = "like_ids"
+	case query.CreatedAfter != nil:
+		field = "created_after"
+	case query.CreatedBefore != nil:
+		field = "created_before"
+	case query.OrderBy != pb.LikesQuery_SAVED:
+		field = "order_by"
+	case query.Limit != 0:
+		field = "limit"
+	default:
+		return nil
+	}
+	return status.Error(codes.InvalidArgument, fmt.Sprintf("%s does not support %s", method, field))
+}
+
+// filterLikes returns the likes that match the filters of a LikesQuery, in
+// its order and up to its limit.
+func filterLikes(query *pb.LikesQuery, likes []*pb.Like) []*pb.Like {
+	likeIds := make(map[string]bool, len(query.LikeIds))
+	for _, id := range query.LikeIds {
+		likeIds[id] = true
+	}
+	var filtered []*pb.Like
+	for _, like := range likes {
+		switch {
+		case query.Liked == pb.LikesQuery_LIKED_ONLY && !(isVote(like) && like.Liked):
+		case query.Liked == pb.LikesQuery_DISLIKED_ONLY && !(isVote(like) && !like.Liked):
+		case query.UserId != "" && like.UserId != query.UserId:
+		case len(likeIds) > 0 && !likeIds[like.Id]:
+		case query.CreatedAfter != nil && (like.CreatedAt == nil || timestampBefore(like.CreatedAt, query.CreatedAfter)):
+		case query.CreatedBefore != nil && (like.CreatedAt == nil || !timestampBefore(like.CreatedAt, query.CreatedBefore)):
+		default:
+			filtered = append(filtered, like)
+		}
+	}
+	switch query.OrderBy {
+	case pb.LikesQuery_CREATED_AT_ASC:
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return timestampBefore(filtered[i].CreatedAt, filtered[j].CreatedAt)
+		})
+	case pb.LikesQuery_CREATED_AT_DESC:
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return timestampBefore(filtered[j].CreatedAt, filtered[i].CreatedAt)
+		})
+	case pb.LikesQuery_ID:
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return filtered[i].Id < filtered[j].Id
+		})
+	}
+	if query.Limit > 0 && len(filtered) > int(query.Limit) {
+		filtered = filtered[:query.Limit]
+	}
+	return filtered
+}
+
+// timestampBefore reports whether a is before b, where a missing timestamp is
+// before every other.
+func timestampBefore(a, b *timestamp.Timestamp) bool {
+	if a == nil || b == nil {
+		return a == nil && b != nil
+	}
+	if a.Seconds != b.Seconds {
+		return a.Seconds < b.Seconds
+	}
+	return a.Nanos < b.Nanos
+}
@@ -0,0 +1,43 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+func TestGetLikesSummaryRejectsFilters(t *testing.T) {
+	beer := &pb.RefType{Name: "beer", Id: "1"}
+	tests := []struct {
+		name  string
+		query *pb.LikesQuery
+		want  codes.Code
+	}{
+		{"no filters", &pb.LikesQuery{RefType: beer}, codes.OK},
+		{"liked", &pb.LikesQuery{RefType: beer, Liked: pb.LikesQuery_LIKED_ONLY}, codes.InvalidArgument},
+		{"user_id", &pb.LikesQuery{RefType: beer, UserId: "u1"}, codes.InvalidArgument},
+		{"like_ids", &pb.LikesQuery{RefType: beer, LikeIds: []string{"1"}}, codes.InvalidArgument},
+		{"created_after", &pb.LikesQuery{RefType: beer, CreatedAfter: &timestamp.Timestamp{Seconds: 1}}, codes.InvalidArgument},
+		{"created_before", &pb.LikesQuery{RefType: beer, CreatedBefore: &timestamp.Timestamp{Seconds: 1}}, codes.InvalidArgument},
+		{"order_by", &pb.LikesQuery{RefType: beer, OrderBy: pb.LikesQuery_ID}, codes.InvalidArgument},
+		{"limit", &pb.LikesQuery{RefType: beer, Limit: 1}, codes.InvalidArgument},
+	}
+	s := newTestServer()
+	for _, test := range tests {
+		_, err := s.GetLikesSummary(context.Background(), test.query)
+		if code := status.Code(err); code != test.want {
+			t.Errorf("%s: GetLikesSummary returned %v, want %v", test.name, err, test.want)
+		}
+	}
+}
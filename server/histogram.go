@@ -0,0 +1,162 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+const (
+	day  = 24 * 60 * 60
+	week = 7 * day
+	// mondayEpoch is the Unix time of the Monday before the Unix epoch.
+	mondayEpoch = -3 * day
+	// maxHistogramBuckets limits the size of a single histogram response.
+	maxHistogramBuckets = 5000
+)
+
+// bucketCounts are the like and dislike counts of a single time bucket.
+type bucketCounts struct {
+	likes    int32
+	dislikes int32
+}
+
+// rollups pre-aggregates the like and dislike counts of each RefType into
+// day and week buckets, so a histogram query only reads one entry per bucket.
+type rollups struct {
+	buckets map[pb.HistogramQuery_BucketSize]map[string]map[int64]*bucketCounts
+}
+
+func newRollups() *rollups {
+	return &rollups{
+		buckets: map[pb.HistogramQuery_BucketSize]map[string]map[int64]*bucketCounts{
+			pb.HistogramQuery_DAY:  make(map[string]map[int64]*bucketCounts),
+			pb.HistogramQuery_WEEK: make(map[string]map[int64]*bucketCounts),
+		},
+	}
+}
+
+// bucketStart returns the Unix time of the start of the bucket containing sec.
+func bucketStart(size pb.HistogramQuery_BucketSize, sec int64) int64 {
+	switch size {
+	case pb.HistogramQuery_WEEK:
+		return floorDiv(sec-mondayEpoch, week)*week + mondayEpoch
+	default:
+		return floorDiv(sec, day) * day
+	}
+}
+
+// bucketWidth returns the length of a bucket in seconds.
+func bucketWidth(size pb.HistogramQuery_BucketSize) int64 {
+	if size == pb.HistogramQuery_WEEK {
+		return week
+	}
+	return day
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// add counts a like in every bucket size. Likes without a created_at, and
+// reactions other than likes and dislikes, are skipped.
+func (r *rollups) add(like *pb.Like) {
+	r.update(like, 1)
+}
+
+// remove uncounts a like from every bucket size.
+func (r *rollups) remove(like *pb.Like) {
+	r.update(like, -1)
+}
+
+func (r *rollups) update(like *pb.Like, delta int32) {
+	if like.CreatedAt == nil || like.RefType == nil || !isVote(like) {
+		return
+	}
+	key := refTypeKey(like.RefType)
+	for size, refTypes := range r.buckets {
+		buckets, ok := refTypes[key]
+		if !ok {
+			buckets = make(map[int64]*bucketCounts)
+			refTypes[key] = buckets
+		}
+		start := bucketStart(size, like.CreatedAt.Seconds)
+		counts, ok := buckets[start]
+		if !ok {
+			counts = &bucketCounts{}
+			buckets[start] = counts
+		}
+		if like.Liked {
+			counts.likes += delta
+		} else {
+			counts.dislikes += delta
+		}
+		// Drop the buckets emptied by deleted or moved likes.
+		if counts.likes == 0 && counts.dislikes == 0 {
+			delete(buckets, start)
+			if len(buckets) == 0 {
+				delete(refTypes, key)
+			}
+		}
+	}
+}
+
+// GetLikesHistogram returns the like and dislike counts of a RefType per time bucket.
+func (s *beerLikesServer) GetLikesHistogram(ctx context.Context, query *pb.HistogramQuery) (*pb.LikesHistogram, error) {
+	if query.RefType == nil {
+		return &pb.LikesHistogram{}, status.Error(codes.InvalidArgument, "ref_type is required")
+	}
+	if _, ok := pb.HistogramQuery_BucketSize_name[int32(query.BucketSize)]; !ok {
+		return &pb.LikesHistogram{}, status.Error(codes.InvalidArgument, fmt.Sprintf("unknown bucket_size: %d", query.BucketSize))
+	}
+	if query.StartTime == nil || query.EndTime == nil {
+		return &pb.LikesHistogram{}, status.Error(codes.InvalidArgument, "start_time and end_time are required")
+	}
+	startTime := time.Now()
+	size := query.BucketSize
+	width := bucketWidth(size)
+	first := bucketStart(size, query.StartTime.Seconds)
+	end := query.EndTime.Seconds
+	if end <= query.StartTime.Seconds {
+		return &pb.LikesHistogram{}, status.Error(codes.InvalidArgument, "end_time must be after start_time")
+	}
+	if n := (end-first)/width + 1; n > maxHistogramBuckets {
+		return &pb.LikesHistogram{}, status.Error(codes.InvalidArgument, fmt.Sprintf("%d buckets exceeds the limit of %d", n, maxHistogramBuckets))
+	}
+
+	histogram := &pb.LikesHistogram{
+		RefType:    query.RefType,
+		BucketSize: size,
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	buckets := s.rollups.buckets[size][refTypeKey(query.RefType)]
+	for start := first; start < end; start += width {
+		bucket := &pb.HistogramBucket{}
+		bucket.StartTime, _ = ptypes.TimestampProto(time.Unix(start, 0))
+		if counts, ok := buckets[start]; ok {
+			bucket.LikeCount = counts.likes
+			bucket.DislikeCount = counts.dislikes
+		}
+		histogram.Buckets = append(histogram.Buckets, bucket)
+	}
+	endTime := time.Now()
+	histogram.ElapsedTime = uint64(endTime.Sub(startTime))
+	return histogram, nil
+}
@@ -0,0 +1,96 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+func TestBucketStart(t *testing.T) {
+	tests := []struct {
+		size pb.HistogramQuery_BucketSize
+		sec  int64
+		want int64
+	}{
+		{pb.HistogramQuery_DAY, 0, 0},
+		{pb.HistogramQuery_DAY, day - 1, 0},
+		{pb.HistogramQuery_DAY, day, day},
+		{pb.HistogramQuery_DAY, -1, -day},
+		{pb.HistogramQuery_DAY, -day, -day},
+		{pb.HistogramQuery_DAY, -day - 1, -2 * day},
+		// 1970-01-01 was a Thursday, so its week started on Monday 1969-12-29.
+		{pb.HistogramQuery_WEEK, 0, -3 * day},
+		{pb.HistogramQuery_WEEK, -3 * day, -3 * day},
+		{pb.HistogramQuery_WEEK, -3*day - 1, -10 * day},
+		{pb.HistogramQuery_WEEK, 4*day - 1, -3 * day},
+		{pb.HistogramQuery_WEEK, 4 * day, 4 * day},
+		{pb.HistogramQuery_WEEK, -10*day - 1, -17 * day},
+	}
+	for _, test := range tests {
+		if got := bucketStart(test.size, test.sec); got != test.want {
+			t.Errorf("bucketStart(%v, %d) = %d, want %d", test.size, test.sec, got, test.want)
+		}
+	}
+}
+
+func TestBucketStartWeeksStartOnMonday(t *testing.T) {
+	for _, date := range []time.Time{
+		time.Date(2018, 8, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2018, 8, 5, 23, 59, 59, 0, time.UTC),
+		time.Date(1969, 7, 20, 20, 17, 0, 0, time.UTC),
+	} {
+		start := time.Unix(bucketStart(pb.HistogramQuery_WEEK, date.Unix()), 0).UTC()
+		if start.Weekday() != time.Monday || start.Hour() != 0 || start.Minute() != 0 || start.Second() != 0 {
+			t.Errorf("the week of %v starts at %v, want midnight on a Monday", date, start)
+		}
+		if date.Before(start) || !date.Before(start.Add(week*time.Second)) {
+			t.Errorf("the week of %v starts at %v, which does not contain it", date, start)
+		}
+	}
+}
+
+func TestGetLikesHistogramRejectsUnknownBucketSize(t *testing.T) {
+	s := newTestServer()
+	query := &pb.HistogramQuery{
+		RefType:    &pb.RefType{Name: "beer", Id: "1"},
+		BucketSize: pb.HistogramQuery_BucketSize(7),
+		StartTime:  &timestamp.Timestamp{Seconds: 0},
+		EndTime:    &timestamp.Timestamp{Seconds: week},
+	}
+	if _, err := s.GetLikesHistogram(context.Background(), query); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetLikesHistogram with bucket_size 7 returned %v, want InvalidArgument", err)
+	}
+	for _, size := range []pb.HistogramQuery_BucketSize{pb.HistogramQuery_DAY, pb.HistogramQuery_WEEK} {
+		query.BucketSize = size
+		if _, err := s.GetLikesHistogram(context.Background(), query); err != nil {
+			t.Errorf("GetLikesHistogram with bucket_size %v returned %v", size, err)
+		}
+	}
+}
+
+func TestRollupsDropEmptyBuckets(t *testing.T) {
+	r := newRollups()
+	like := &pb.Like{Id: "1", RefType: &pb.RefType{Name: "beer", Id: "1"}, Liked: true, CreatedAt: &timestamp.Timestamp{Seconds: day}}
+	r.add(like)
+	moved := &pb.Like{Id: "1", RefType: &pb.RefType{Name: "beer", Id: "2"}, Liked: true, CreatedAt: like.CreatedAt}
+	r.remove(like)
+	r.add(moved)
+	r.remove(moved)
+	for size, refTypes := range r.buckets {
+		if len(refTypes) != 0 {
+			t.Errorf("the %v rollups have %d RefTypes after every like was removed, want 0", size, len(refTypes))
+		}
+	}
+}
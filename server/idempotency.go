@@ -0,0 +1,148 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// idempotencyKeyHeader is the request metadata key of an idempotency key.
+	idempotencyKeyHeader = "idempotency-key"
+	// idempotentReplayHeader is set on the responses of replayed writes.
+	idempotentReplayHeader = "idempotent-replayed"
+	// maxIdempotencyKeyLength limits the size of an idempotency key.
+	maxIdempotencyKeyLength = 255
+)
+
+// writeMethods are the RPCs that accept an idempotency key.
+var writeMethods = map[string]bool{
+	"/beerlikes.BeerLikes/CreateLike":   true,
+	"/beerlikes.BeerLikes/DeleteLike":   true,
+	"/beerlikes.BeerLikes/UndeleteLike": true,
+	"/beerlikes.BeerLikes/UpdateLike":   true,
+	"/beerlikes.BeerLikes/ToggleLike":   true,
+}
+
+// idempotentCall is a write made with an idempotency key.
+type idempotentCall struct {
+	method      string
+	fingerprint [sha256.Size]byte
+	done        chan struct{} // closed when resp and err are set
+	resp        interface{}
+	err         error
+	expires     time.Time // zero while the call is running
+}
+
+// idempotencyStore remembers the responses of the writes made with an
+// idempotency key for a window, so that a retried write returns the original
+// response instead of being applied twice. Failed writes are forgotten, so
+// they can be retried with the same key.
+type idempotencyStore struct {
+	mu     sync.Mutex
+	window time.Duration
+	calls  map[string]*idempotentCall
+}
+
+func newIdempotencyStore(window time.Duration) *idempotencyStore {
+	s := &idempotencyStore{window: window, calls: make(map[string]*idempotentCall)}
+	go s.sweepLoop(time.Minute)
+	return s
+}
+
+func (c *idempotentCall) expired(now time.Time) bool {
+	return !c.expires.IsZero() && now.After(c.expires)
+}
+
+// sweepLoop drops the expired calls every interval.
+func (s *idempotencyStore) sweepLoop(interval time.Duration) {
+	for range time.Tick(interval) {
+		now := time.Now()
+		s.mu.Lock()
+		for key, call := range s.calls {
+			if call.expired(now) {
+				delete(s.calls, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// unaryInterceptor applies the idempotency key of a write, if it has one.
+func (s *idempotencyStore) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !writeMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	keys := md.Get(idempotencyKeyHeader)
+	if len(keys) == 0 || keys[0] == "" {
+		return handler(ctx, req)
+	}
+	key := keys[0]
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("%s is longer than %d bytes", idempotencyKeyHeader, maxIdempotencyKeyLength))
+	}
+	// Scope the key by tenant so a tenant cannot replay the writes of another.
+	tenantKey := tenantName(ctx) + "/" + key
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return handler(ctx, req)
+	}
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to fingerprint the request: %v", err))
+	}
+	fingerprint := sha256.Sum256(b)
+
+	s.mu.Lock()
+	call, ok := s.calls[tenantKey]
+	if !ok || call.expired(time.Now()) {
+		call = &idempotentCall{method: info.FullMethod, fingerprint: fingerprint, done: make(chan struct{})}
+		s.calls[tenantKey] = call
+		s.mu.Unlock()
+		resp, err := handler(ctx, req)
+		s.mu.Lock()
+		call.resp, call.err = resp, err
+		if err != nil {
+			delete(s.calls, tenantKey)
+		} else {
+			call.expires = time.Now().Add(s.window)
+		}
+		close(call.done)
+		s.mu.Unlock()
+		return resp, err
+	}
+	s.mu.Unlock()
+
+	if call.method != info.FullMethod || call.fingerprint != fingerprint {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("%s %q was used for a different request", idempotencyKeyHeader, key))
+	}
+	// Wait for the original write if it is still running.
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, ctx.Err().Error())
+	}
+	if call.err != nil {
+		return nil, call.err
+	}
+	if changes := auditChangesFrom(ctx); changes != nil {
+		changes.replay()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(idempotentReplayHeader, "true"))
+	return call.resp, nil
+}
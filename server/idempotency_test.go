@@ -0,0 +1,106 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// headerStream keeps the response header set by a handler.
+type headerStream struct {
+	header metadata.MD
+}
+
+func (s *headerStream) Method() string {
+	return "/beerlikes.BeerLikes/CreateLike"
+}
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *headerStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *headerStream) SetTrailer(md metadata.MD) error {
+	return nil
+}
+
+// idempotentCreate calls CreateLike through the idempotency interceptor with
+// a key and the given tenant, and returns the response, its header and
+// whether the handler ran.
+func idempotentCreate(store *idempotencyStore, tenant, key string, like *pb.Like, handler grpc.UnaryHandler) (interface{}, metadata.MD, bool, error) {
+	stream := &headerStream{}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(tenantHeader, tenant, idempotencyKeyHeader, key))
+	ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+	ran := false
+	resp, err := store.unaryInterceptor(ctx, like, &grpc.UnaryServerInfo{FullMethod: "/beerlikes.BeerLikes/CreateLike"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		ran = true
+		return handler(ctx, req)
+	})
+	return resp, stream.header, ran, err
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	beer := &pb.RefType{Name: "beer", Id: "1"}
+	created := 0
+	create := func(ctx context.Context, req interface{}) (interface{}, error) {
+		created++
+		return &pb.Like{Id: "created", RefType: req.(*pb.Like).RefType, Version: uint64(created)}, nil
+	}
+	fail := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+	store := &idempotencyStore{window: time.Hour, calls: make(map[string]*idempotentCall)}
+	like := &pb.Like{RefType: beer, Liked: true}
+
+	first, header, ran, err := idempotentCreate(store, defaultTenant, "k1", like, create)
+	if err != nil || !ran || len(header.Get(idempotentReplayHeader)) != 0 {
+		t.Fatalf("the first call returned %v, ran %v with header %v, want it to run without %s", err, ran, header, idempotentReplayHeader)
+	}
+	replayed, header, ran, err := idempotentCreate(store, defaultTenant, "k1", like, create)
+	if err != nil || ran {
+		t.Errorf("the replay returned %v and ran %v, want it replayed", err, ran)
+	}
+	if replayed != first {
+		t.Errorf("the replay returned %v, want the original response %v", replayed, first)
+	}
+	if values := header.Get(idempotentReplayHeader); len(values) != 1 || values[0] != "true" {
+		t.Errorf("the replay has the header %v, want %s set", header, idempotentReplayHeader)
+	}
+
+	_, _, ran, err = idempotentCreate(store, defaultTenant, "k1", &pb.Like{RefType: beer, Liked: false}, create)
+	if status.Code(err) != codes.InvalidArgument || ran {
+		t.Errorf("reusing a key for a different request returned %v and ran %v, want InvalidArgument", err, ran)
+	}
+
+	if _, _, _, err := idempotentCreate(store, defaultTenant, "k2", like, fail); status.Code(err) != codes.Unavailable {
+		t.Fatalf("the failed call returned %v, want Unavailable", err)
+	}
+	if _, _, ran, err := idempotentCreate(store, defaultTenant, "k2", like, create); err != nil || !ran {
+		t.Errorf("retrying a failed call returned %v and ran %v, want it to run again", err, ran)
+	}
+
+	other, header, ran, err := idempotentCreate(store, "wine", "k1", like, create)
+	if err != nil || !ran || len(header.Get(idempotentReplayHeader)) != 0 {
+		t.Errorf("the key of another tenant returned %v and ran %v with header %v, want it to run", err, ran, header)
+	}
+	if other == first {
+		t.Errorf("another tenant was replayed the response %v", first)
+	}
+}
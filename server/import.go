@@ -0,0 +1,63 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// maxImportErrors limits the number of errors reported in an ImportSummary.
+const maxImportErrors = 1000
+
+// ImportLikes stores a stream of likes, replacing any like with the same id.
+// Invalid likes are counted and reported instead of failing the import.
+func (s *beerLikesServer) ImportLikes(stream pb.BeerLikesAdmin_ImportLikesServer) error {
+	startTime := time.Now()
+	summary := &pb.ImportSummary{}
+	for index := int32(0); ; index++ {
+		like, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if like, err = newLike(like); err == nil {
+			s.mu.Lock()
+			event := &pb.LikeEvent{Type: pb.LikeEvent_LIKE, Time: ptypes.TimestampNow(), Like: like}
+			if current := s.findLike(like.Id); current != nil {
+				like.Version = current.Version + 1
+				if !proto.Equal(current.RefType, like.RefType) {
+					event.PreviousRefType = current.RefType
+				}
+			}
+			err = s.recordEvent(stream.Context(), event)
+			s.mu.Unlock()
+			if err != nil {
+				return status.Error(codes.Internal, fmt.Sprintf("failed to record %s: %v", like.Id, err))
+			}
+			summary.Imported++
+			continue
+		}
+		summary.Rejected++
+		if len(summary.Errors) < maxImportErrors {
+			summary.Errors = append(summary.Errors, &pb.ImportError{Index: index, Message: status.Convert(err).Message()})
+		}
+	}
+	endTime := time.Now()
+	summary.ElapsedTime = uint64(endTime.Sub(startTime))
+	return stream.SendAndClose(summary)
+}
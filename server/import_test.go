@@ -0,0 +1,77 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// importStream sends likes to ImportLikes and keeps its summary.
+type importStream struct {
+	grpc.ServerStream
+	likes   []*pb.Like
+	summary *pb.ImportSummary
+}
+
+func (s *importStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *importStream) Recv() (*pb.Like, error) {
+	if len(s.likes) == 0 {
+		return nil, io.EOF
+	}
+	like := s.likes[0]
+	s.likes = s.likes[1:]
+	return like, nil
+}
+
+func (s *importStream) SendAndClose(summary *pb.ImportSummary) error {
+	s.summary = summary
+	return nil
+}
+
+func TestImportLikesMovingALikeSetsPreviousRefType(t *testing.T) {
+	s := newTestServer()
+	beer1 := &pb.RefType{Name: "beer", Id: "1"}
+	beer2 := &pb.RefType{Name: "beer", Id: "2"}
+	stream := &importStream{likes: []*pb.Like{{Id: "a", RefType: beer1, Liked: true}}}
+	if err := s.ImportLikes(stream); err != nil {
+		t.Fatal(err)
+	}
+	_, events := s.watchers.subscribe()
+	stream = &importStream{likes: []*pb.Like{{Id: "a", RefType: beer1, Liked: false}, {Id: "a", RefType: beer2, Liked: false}}}
+	if err := s.ImportLikes(stream); err != nil {
+		t.Fatal(err)
+	}
+	if stream.summary.Imported != 2 {
+		t.Fatalf("imported %d likes, want 2", stream.summary.Imported)
+	}
+	if event := <-events; event.PreviousRefType != nil {
+		t.Errorf("replacing a like within its RefType recorded the previous RefType %v", event.PreviousRefType)
+	}
+	event := <-events
+	if !proto.Equal(event.PreviousRefType, beer1) {
+		t.Errorf("moving a like from %v recorded the previous RefType %v", beer1, event.PreviousRefType)
+	}
+	if !matchesRefType(&pb.LikesQuery{RefType: beer1}, event.PreviousRefType) {
+		t.Errorf("a watcher of %v does not see the like moving away", beer1)
+	}
+	if likes := s.index.refType(beer1); len(likes) != 0 {
+		t.Errorf("%v still has the likes %v", beer1, likes)
+	}
+	if like := s.findLike("a"); like.Version != 3 || !proto.Equal(like.RefType, beer2) {
+		t.Errorf("the like is %v, want version 3 of %v", like, beer2)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// maxQueryIds limits the number of ids of a LikesQuery.
+const maxQueryIds = 1000
+
+// likeIndex indexes the saved likes by RefType, so that the likes of a RefType,
+// of every RefType with a name, or of the ids with a prefix are found without
+// a scan of all the likes.
+type likeIndex struct {
+	byRefType map[string][]*pb.Like // likes of each RefType, by refTypeKey, in the order they were saved
+	ids       map[string][]string   // sorted ids of the RefTypes with likes, by RefType name
+}
+
+func newLikeIndex() *likeIndex {
+	return &likeIndex{byRefType: make(map[string][]*pb.Like), ids: make(map[string][]string)}
+}
+
+// add indexes a like.
+func (x *likeIndex) add(like *pb.Like) {
+	key := refTypeKey(like.RefType)
+	if _, ok := x.byRefType[key]; !ok {
+		name, id := like.RefType.GetName(), like.RefType.GetId()
+		ids := x.ids[name]
+		i := sort.SearchStrings(ids, id)
+		ids = append(ids, "")
+		copy(ids[i+1:], ids[i:])
+		ids[i] = id
+		x.ids[name] = ids
+	}
+	x.byRefType[key] = append(x.byRefType[key], like)
+}
+
+// remove drops a like from the index.
+func (x *likeIndex) remove(like *pb.Like) {
+	key := refTypeKey(like.RefType)
+	likes := x.byRefType[key]
+	for i, item := range likes {
+		if item == like {
+			likes = append(likes[:i], likes[i+1:]...)
+			break
+		}
+	}
+	if len(likes) > 0 {
+		x.byRefType[key] = likes
+		return
+	}
+	delete(x.byRefType, key)
+	name, id := like.RefType.GetName(), like.RefType.GetId()
+	ids := x.ids[name]
+	if i := sort.SearchStrings(ids, id); i < len(ids) && ids[i] == id {
+		ids = append(ids[:i], ids[i+1:]...)
+	}
+	if len(ids) == 0 {
+		delete(x.ids, name)
+		return
+	}
+	x.ids[name] = ids
+}
+
+// replace swaps a like for a newer version of it, keeping its position among
+// the likes of its RefType unless it moved to another RefType.
+func (x *likeIndex) replace(old, like *pb.Like) {
+	key := refTypeKey(like.RefType)
+	if refTypeKey(old.RefType) == key {
+		for i, item := range x.byRefType[key] {
+			if item == old {
+				x.byRefType[key][i] = like
+				return
+			}
+		}
+	}
+	x.remove(old)
+	x.add(like)
+}
+
+// refType returns the likes of a RefType.
+func (x *likeIndex) refType(refType *pb.RefType) []*pb.Like {
+	return x.byRefType[refTypeKey(refType)]
+}
+
+// query returns the likes matched by a validated LikesQuery, grouped by
+// RefType in the order of their ids, and in the order they were first saved
+// within a RefType. Repeated ids are only matched once.
+func (x *likeIndex) query(query *pb.LikesQuery) []*pb.Like {
+	name := query.RefType.Name
+	var ids []string
+	switch {
+	case len(query.Ids) > 0:
+		ids = append([]string(nil), query.Ids...)
+		sort.Strings(ids)
+		unique := ids[:1]
+		for _, id := range ids[1:] {
+			if id != unique[len(unique)-1] {
+				unique = append(unique, id)
+			}
+		}
+		ids = unique
+	case query.RefType.Id != "":
+		ids = []string{query.RefType.Id}
+	default:
+		all := x.ids[name]
+		i := sort.SearchStrings(all, query.IdPrefix)
+		j := i
+		for j < len(all) && strings.HasPrefix(all[j], query.IdPrefix) {
+			j++
+		}
+		ids = all[i:j]
+	}
+	var likes []*pb.Like
+	for _, id := range ids {
+		likes = append(likes, x.byRefType[refTypeKey(&pb.RefType{Name: name, Id: id})]...)
+	}
+	return likes
+}
+
+// exact reports whether a LikesQuery matches a single RefType.
+func exact(query *pb.LikesQuery) bool {
+	return query.RefType.GetId() != ""
+}
+
+// validateLikesQuery checks the RefType matching of a LikesQuery: an exact
+// RefType, or a RefType name with an optional id_prefix or ids.
+func validateLikesQuery(query *pb.LikesQuery) error {
+	if query.RefType == nil {
+		return status.Error(codes.InvalidArgument, "ref_type is required")
+	}
+	if query.IdPrefix != "" || len(query.Ids) > 0 {
+		if query.RefType.Id != "" {
+			return status.Error(codes.InvalidArgument, "ref_type.id cannot be combined with id_prefix or ids")
+		}
+		if query.IdPrefix != "" && len(query.Ids) > 0 {
+			return status.Error(codes.InvalidArgument, "id_prefix cannot be combined with ids")
+		}
+	}
+	if query.RefType.Name == "" && !exact(query) {
+		return status.Error(codes.InvalidArgument, "ref_type.name is required")
+	}
+	if len(query.Ids) > maxQueryIds {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("%d ids exceeds the limit of %d", len(query.Ids), maxQueryIds))
+	}
+	return nil
+}
+
+// matchesRefType reports whether a RefType is matched by a validated LikesQuery.
+func matchesRefType(query *pb.LikesQuery, refType *pb.RefType) bool {
+	if refType.GetName() != query.RefType.Name {
+		return false
+	}
+	id := refType.GetId()
+	switch {
+	case len(query.Ids) > 0:
+		for _, queryID := range query.Ids {
+			if id == queryID {
+				return true
+			}
+		}
+		return false
+	case query.RefType.Id != "":
+		return id == query.RefType.Id
+	default:
+		return strings.HasPrefix(id, query.IdPrefix)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+func TestRefTypeKeyWithSlashes(t *testing.T) {
+	a := &pb.RefType{Name: "a/b", Id: "c"}
+	b := &pb.RefType{Name: "a", Id: "b/c"}
+	if refTypeKey(a) == refTypeKey(b) {
+		t.Fatalf("%v and %v have the same key %q", a, b, refTypeKey(a))
+	}
+
+	x := newLikeIndex()
+	x.add(&pb.Like{Id: "1", RefType: a, Liked: true})
+	if likes := x.refType(b); len(likes) != 0 {
+		t.Errorf("the likes of %v include %v", b, likes)
+	}
+	if likes := x.query(&pb.LikesQuery{RefType: &pb.RefType{Name: "a"}, IdPrefix: "b"}); len(likes) != 0 {
+		t.Errorf("the likes of a with an id prefix b include %v", likes)
+	}
+	if likes := x.refType(a); len(likes) != 1 {
+		t.Errorf("%v has %d likes, want 1", a, len(likes))
+	}
+}
+
+func TestGetLikesSummaryRejectsIdQueries(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *pb.LikesQuery
+		want  codes.Code
+	}{
+		{"exact RefType", &pb.LikesQuery{RefType: &pb.RefType{Name: "beer", Id: "1"}}, codes.OK},
+		{"missing RefType", &pb.LikesQuery{}, codes.InvalidArgument},
+		{"id_prefix", &pb.LikesQuery{RefType: &pb.RefType{Name: "beer"}, IdPrefix: "1"}, codes.InvalidArgument},
+		{"ids", &pb.LikesQuery{RefType: &pb.RefType{Name: "beer"}, Ids: []string{"1", "2"}}, codes.InvalidArgument},
+		{"id with id_prefix", &pb.LikesQuery{RefType: &pb.RefType{Name: "beer", Id: "1"}, IdPrefix: "1"}, codes.InvalidArgument},
+	}
+	s := newTestServer()
+	for _, test := range tests {
+		_, err := s.GetLikesSummary(context.Background(), test.query)
+		if code := status.Code(err); code != test.want {
+			t.Errorf("%s: GetLikesSummary returned %v, want %v", test.name, err, test.want)
+		}
+	}
+}
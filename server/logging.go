@@ -0,0 +1,211 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+const (
+	// requestIDHeader is the request and response metadata key of the id of
+	// a call, which correlates it with its log entry. An id is generated for
+	// the calls without one.
+	requestIDHeader = "x-request-id"
+	// maxRequestIDLength bounds the length of a request id from a client.
+	maxRequestIDLength = 128
+)
+
+// logging holds the current log format, as logrus has no getter for it.
+var logging struct {
+	sync.Mutex
+	format string
+	saved  log.Level // the level to restore on the next SIGUSR1
+	debug  bool      // whether SIGUSR1 switched to the debug level
+}
+
+// parseLogLevel validates a log level.
+func parseLogLevel(level string) (log.Level, error) {
+	switch level {
+	case "debug", "info", "warning", "error":
+		return log.ParseLevel(level)
+	}
+	return 0, fmt.Errorf("log level must be debug, info, warning or error: %q", level)
+}
+
+// setLogging sets the log level and format, leaving the empty ones unchanged.
+func setLogging(level, format string) error {
+	var parsed log.Level
+	if level != "" {
+		var err error
+		if parsed, err = parseLogLevel(level); err != nil {
+			return err
+		}
+	}
+	var formatter log.Formatter
+	switch format {
+	case "":
+	case "text":
+		formatter = &log.TextFormatter{}
+	case "json":
+		formatter = &log.JSONFormatter{}
+	default:
+		return fmt.Errorf("log format must be text or json: %q", format)
+	}
+	logging.Lock()
+	defer logging.Unlock()
+	if level != "" {
+		log.SetLevel(parsed)
+		logging.debug = false
+	}
+	if formatter != nil {
+		log.SetFormatter(formatter)
+		logging.format = format
+	}
+	return nil
+}
+
+// currentLogging returns the current log level and format.
+func currentLogging() *pb.LoggingConfig {
+	logging.Lock()
+	defer logging.Unlock()
+	level := log.GetLevel().String()
+	return &pb.LoggingConfig{Level: level, Format: logging.format}
+}
+
+// toggleDebugLogging switches to the debug level on SIGUSR1, and back to the
+// previous level on the next one.
+func toggleDebugLogging() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	for range signals {
+		logging.Lock()
+		if logging.debug {
+			log.SetLevel(logging.saved)
+		} else {
+			logging.saved = log.GetLevel()
+			log.SetLevel(log.DebugLevel)
+		}
+		logging.debug = !logging.debug
+		logging.Unlock()
+		log.Infof("Log level set to %s", log.GetLevel())
+	}
+}
+
+// SetLogging changes the log level and format of the server.
+func (t *tenantServer) SetLogging(ctx context.Context, req *pb.LoggingConfig) (*pb.LoggingConfig, error) {
+	if err := setLogging(req.Level, req.Format); err != nil {
+		return &pb.LoggingConfig{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	current := currentLogging()
+	if req.Level != "" || req.Format != "" {
+		log.Infof("Log level set to %s and format to %s", current.Level, current.Format)
+	}
+	return current, nil
+}
+
+// parseLogSampling parses a comma separated list of method=rate pairs, e.g.
+// ListLikes=0.1 to log one in ten of the successful ListLikes calls.
+func parseLogSampling(value string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		i := strings.Index(pair, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("log sampling must be method=rate pairs: %q", pair)
+		}
+		rate, err := strconv.ParseFloat(pair[i+1:], 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("log sampling rate of %s must be between 0 and 1: %q", pair[:i], pair[i+1:])
+		}
+		rates[pair[:i]] = rate
+	}
+	return rates, nil
+}
+
+// logSampler returns a logging decider that logs the failed calls and a
+// sample of the successful calls of the methods with a rate, by method name.
+func logSampler(rates map[string]float64) func(fullMethodName string, err error) bool {
+	return func(fullMethodName string, err error) bool {
+		rate, ok := rates[fullMethodName[strings.LastIndex(fullMethodName, "/")+1:]]
+		return err != nil || !ok || rand.Float64() < rate
+	}
+}
+
+// requestID returns the id of a call from its metadata, or a new one.
+func requestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(requestIDHeader); len(values) > 0 && values[0] != "" && len(values[0]) <= maxRequestIDLength {
+		return values[0]
+	}
+	return newID()
+}
+
+// withRequestID adds the request id of a call to the details of its error,
+// unless it has one.
+func withRequestID(err error, id string) error {
+	st := status.Convert(err)
+	for _, detail := range st.Details() {
+		if _, ok := detail.(*errdetails.RequestInfo); ok {
+			return err
+		}
+	}
+	withDetails, detailsErr := st.WithDetails(&errdetails.RequestInfo{RequestId: id})
+	if detailsErr != nil {
+		return err
+	}
+	return withDetails.Err()
+}
+
+// requestIDUnaryInterceptor tags the log entry of a call with its request id,
+// returns the id in the response header and trailer and adds it to the
+// details of an error.
+func requestIDUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	id := requestID(ctx)
+	grpc_ctxtags.Extract(ctx).Set("request_id", id)
+	md := metadata.Pairs(requestIDHeader, id)
+	grpc.SetHeader(ctx, md)
+	grpc.SetTrailer(ctx, md)
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return resp, withRequestID(err, id)
+	}
+	return resp, nil
+}
+
+// requestIDStreamInterceptor is requestIDUnaryInterceptor for streams.
+func requestIDStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	id := requestID(stream.Context())
+	grpc_ctxtags.Extract(stream.Context()).Set("request_id", id)
+	md := metadata.Pairs(requestIDHeader, id)
+	stream.SetHeader(md)
+	stream.SetTrailer(md)
+	if err := handler(srv, stream); err != nil {
+		return withRequestID(err, id)
+	}
+	return nil
+}
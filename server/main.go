@@ -13,20 +13,29 @@
 package main
 
 import (
+	"crypto/rand"
+	cryptotls "crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net"
 	"os"
+	"sort"
+	"sync"
 	"time"
 
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
 
+	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
@@ -38,30 +47,67 @@ import (
 
 var (
 	tls        = flag.Bool("tls", false, "Connection uses TLS if true, else plain TCP")
-	certFile   = flag.String("cert_file", "", "The TLS cert file")
-	keyFile    = flag.String("key_file", "", "The TLS key file")
+	certFile   = flag.String("cert_file", "", "The TLS cert file, required with -tls")
+	keyFile    = flag.String("key_file", "", "The TLS key file, required with -tls")
+	clientCA   = flag.String("client_ca_file", "", "With -tls, the CA certificates client certificates are verified with; the common name of a verified certificate is the principal of its calls")
 	jsonDBFile = flag.String("json_db_file", "testdata/beer_likes_db.json", "A json file containing a list of features")
 	port       = flag.Int("port", 10000, "The server port")
 	host       = flag.String("host", "127.0.0.1", "The server host ip")
+	confidence = flag.Float64("score_confidence", 0.95, "The confidence level used for the Wilson score lower bound")
+
+	eventLogFile     = flag.String("event_log_file", "", "An append-only file of like events that the likes are rebuilt from at startup, empty keeps the likes in memory only")
+	snapshotInterval = flag.Duration("snapshot_interval", 5*time.Minute, "How often to snapshot the likes and rotate the event log, 0 disables snapshots")
+	archiveRetention = flag.Int("archive_retention", 3, "How many rotated event log files <file>.<sequence> are kept, 0 deletes them once a snapshot covers them")
+	fsync            = flag.String("fsync", "interval", "When to sync the event log to disk: always, interval or never")
+	fsyncInterval    = flag.Duration("fsync_interval", time.Second, "How often the event log is synced with -fsync interval")
+
+	idempotencyWindow = flag.Duration("idempotency_window", 24*time.Hour, "How long the response of a write with an idempotency-key is replayed for, 0 ignores the keys")
+
+	tombstoneRetention = flag.Duration("tombstone_retention", 30*24*time.Hour, "How long deleted likes are kept before they are purged, 0 keeps them forever")
+	compactionInterval = flag.Duration("compaction_interval", time.Hour, "How often deleted likes older than -tombstone_retention are purged")
+
+	tenants          = flag.String("tenants", "", "Comma separated tenants besides the default tenant, each with its own -json_db_file and -event_log_file named <file>.<tenant>.<ext>")
+	tenantPrincipals = flag.String("tenant_principals", "", "Comma separated principal=tenant pairs of the client certificate common names allowed to use each tenant; every other caller can only use the default tenant")
+	adminPrincipals  = flag.String("admin_principals", "", "Comma separated client certificate common names that ListTenants shows every tenant to")
+
+	configFile  = flag.String("config", "", "A YAML config file of the settings not given as flags or environment variables, also read from $BEER_LIKES_CONFIG")
+	logLevel    = flag.String("log_level", "debug", "The lowest severity that is logged: debug, info, warning or error")
+	logFormat   = flag.String("log_format", "text", "The format of the log entries: text or json")
+	logSampling = flag.String("log_sampling", "", "Comma separated method=rate pairs of the share of successful calls of a method that are logged, e.g. ListLikes=0.1")
+
+	maxRecvMsgSize = flag.Int("max_recv_msg_size", 4<<20, "The largest request message in bytes the server accepts")
+	auditSinks     = flag.String("audit_sinks", "", "Comma separated sinks of the audit records of the writes: file, stdout or stream, empty disables auditing")
+	auditLogFile   = flag.String("audit_file", "", "The hash chained audit log of the file audit sink")
+
+	maxConcurrentStreams = flag.Uint("max_concurrent_streams", 0, "The most concurrent streams of each client connection, 0 for no limit")
 )
 
 type beerLikesServer struct {
-	savedLikes []*pb.Like // read-only after initialized
-	//mu         sync.Mutex // protects routeNotes
-	//routeNotes map[string][]*pb.RouteNote
+	mu         sync.RWMutex // protects savedLikes, positions, index, rollups, versions and refTypes
+	tenant     string
+	savedLikes []*pb.Like     // in the order they were first saved
+	positions  map[string]int // position of each like in savedLikes, by id
+	index      *likeIndex     // savedLikes by RefType
+	z          float64        // standard normal quantile for the score confidence level
+	rollups    *rollups       // time bucketed counts of savedLikes
+	versions   *versions      // versions of savedLikes per RefType
+	refTypes   *refTypeTree   // parents of the RefTypes
+	summaries  *summaryCache
+	events     *eventLog // nil when the event log is disabled
+	watchers   *watchers // subscribers to the recorded events
+	audit      *auditor  // nil when auditing is disabled
+
+	snapshotSequence uint64 // only used by the snapshot goroutine
 }
 
 // Init
 func init() {
-	// Log as JSON instead of the default ASCII formatter.
-	//log.SetFormatter(&log.JSONFormatter{})
-
 	// Output to stdout instead of the default stderr
 	// Can be any io.Writer, see below for File example
 	log.SetOutput(os.Stdout)
 
-	// Only log the debug severity or above.
-	log.SetLevel(log.DebugLevel)
+	// The level and format are set from -log_level and -log_format, and can
+	// be changed with SetLogging and SIGUSR1.
 }
 
 // GetLike returns the feature at the given Like.
@@ -69,58 +115,544 @@ func (s *beerLikesServer) GetLike(ctx context.Context, query *pb.LikeQuery) (*pb
 	if query == nil {
 		return &pb.Like{}, status.Error(codes.InvalidArgument, fmt.Sprintf("%s is not valid", query.Id))
 	}
-	for _, item := range s.savedLikes {
-		if item.Id == query.Id {
-			return item, nil
-		}
+	if err := validateMask("read_mask", query.ReadMask, readPaths); err != nil {
+		return &pb.Like{}, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if item := s.findLike(query.Id); item != nil && (item.DeletedAt == nil || query.IncludeDeleted) {
+		return maskLike(item, query.ReadMask), nil
 	}
 	// No like was found, return an unnamed like
 	return &pb.Like{}, status.Error(codes.NotFound, fmt.Sprintf("%s was not found", query.Id))
 }
 
 // ListLikes lists all likes contained within the given bounding Like.
+// The likes are filtered, ordered and limited by the rest of the query.
+// The likes of a single RefType are sent with its version in the header.
 func (s *beerLikesServer) ListLikes(query *pb.LikesQuery, stream pb.BeerLikes_ListLikesServer) error {
-	if query.RefType == nil {
-		return status.Error(codes.InvalidArgument, fmt.Sprintf("%s is not valid", query.RefType.Id))
+	if err := validateLikesQuery(query); err != nil {
+		return err
+	}
+	if err := validateLikesFilters(query); err != nil {
+		return err
+	}
+	if err := validateMask("read_mask", query.ReadMask, readPaths); err != nil {
+		return err
+	}
+	// Collect the likes first so a slow client does not hold the lock.
+	var likes []*pb.Like
+	s.mu.RLock()
+	for _, item := range s.index.query(query) {
+		if item.DeletedAt == nil || query.IncludeDeleted {
+			likes = append(likes, item)
+		}
+	}
+	version := s.versions.get(query.RefType)
+	s.mu.RUnlock()
+	likes = filterLikes(query, likes)
+	if exact(query) {
+		if err := stream.SetHeader(versionMetadata(version)); err != nil {
+			return err
+		}
 	}
 	sent := false
-	for _, item := range s.savedLikes {
-		if proto.Equal(item.RefType, query.RefType) {
-			if err := stream.Send(item); err != nil {
-				return err
-			}
-			sent = true
+	for _, item := range likes {
+		if err := stream.Send(maskLike(item, query.ReadMask)); err != nil {
+			return err
 		}
+		sent = true
 	}
 
 	if sent == false {
 		// No like was found, return an unnamed like
+		if !exact(query) {
+			return status.Error(codes.NotFound, fmt.Sprintf("no %s was found", query.RefType.Name))
+		}
 		return status.Error(codes.NotFound, fmt.Sprintf("%s was not found", query.RefType.Id))
 	}
 	return nil
 }
 
 // GetLikesSummary batch fetches the likes contained within the given bounding Like.
+// With rollup it also includes the likes of the descendants of the RefType.
+// The summaries without the deleted likes are memoized per RefType until its
+// likes change, and a summary without the likes is returned if they are still
+// at the if_version_changed version.
 func (s *beerLikesServer) GetLikesSummary(ctx context.Context, query *pb.LikesQuery) (*pb.LikesSummary, error) {
-	var total int32
+	if err := validateLikesQuery(query); err != nil {
+		return &pb.LikesSummary{}, err
+	}
+	if query.IdPrefix != "" || len(query.Ids) > 0 {
+		return &pb.LikesSummary{}, status.Error(codes.InvalidArgument, "GetLikesSummary summarizes a single RefType and does not support id_prefix or ids")
+	}
+	if err := validateUnfiltered("GetLikesSummary", query); err != nil {
+		return &pb.LikesSummary{}, err
+	}
+	if err := validateMask("read_mask", query.ReadMask, readPaths); err != nil {
+		return &pb.LikesSummary{}, err
+	}
 	var likes []*pb.Like
 	startTime := time.Now()
-	for _, item := range s.savedLikes {
-		if proto.Equal(item.RefType, query.RefType) {
-			if item.Liked {
-				total++
-			} else {
-				total--
+	s.mu.RLock()
+	version := s.versions.get(query.RefType)
+	notModified := query.IfVersionChanged != 0 && query.IfVersionChanged == version
+	memoized := !query.IncludeDeleted && !query.Rollup
+	var summary *pb.LikesSummary
+	if memoized {
+		summary = s.summaries.get(query.RefType, version)
+	}
+	if !notModified && summary == nil && query.Rollup {
+		for _, item := range s.savedLikes {
+			if s.refTypes.within(item.RefType, query.RefType) {
+				likes = append(likes, item)
 			}
-			likes = append(likes, item)
+		}
+	} else if !notModified && summary == nil {
+		likes = append(likes, s.index.refType(query.RefType)...)
+	}
+	s.mu.RUnlock()
+	grpc.SetHeader(ctx, versionMetadata(version))
+	if notModified {
+		summary = &pb.LikesSummary{RefType: query.RefType, Version: version, NotModified: true}
+	} else if summary == nil {
+		summary = s.summarize(query.RefType, likes)
+		for _, item := range likes {
+			if item.DeletedAt == nil || query.IncludeDeleted {
+				summary.Likes = append(summary.Likes, item)
+			}
+		}
+		summary.Version = version
+		if memoized {
+			s.summaries.put(summary)
 		}
 	}
+	// The cached summary is shared, so the elapsed time is set on a copy.
+	response := *summary
+	response.Likes = maskLikes(summary.Likes, query.ReadMask)
 	endTime := time.Now()
-	return &pb.LikesSummary{
-		Likes:       likes,
-		Total:       total,
-		ElapsedTime: uint64(endTime.Sub(startTime)),
-	}, nil
+	response.ElapsedTime = uint64(endTime.Sub(startTime))
+	return &response, nil
+}
+
+// RankLikes streams the summaries of all the RefTypes with the given name in descending order.
+// With rollup the likes of a RefType are also counted for its ancestors.
+func (s *beerLikesServer) RankLikes(query *pb.RankQuery, stream pb.BeerLikes_RankLikesServer) error {
+	if query.Name == "" {
+		return status.Error(codes.InvalidArgument, "name is required")
+	}
+	if query.Limit < 0 {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("limit %d is not valid", query.Limit))
+	}
+	startTime := time.Now()
+	var keys []string
+	refTypes := make(map[string]*pb.RefType)
+	grouped := make(map[string][]*pb.Like)
+	versions := make(map[string]uint64)
+	s.mu.RLock()
+	for _, item := range s.savedLikes {
+		if item.RefType == nil {
+			continue
+		}
+		groups := []*pb.RefType{item.RefType}
+		if query.Rollup {
+			groups = append(groups, s.refTypes.ancestors(item.RefType)...)
+		}
+		for _, refType := range groups {
+			if refType.Name != query.Name {
+				continue
+			}
+			key := refTypeKey(refType)
+			if _, ok := grouped[key]; !ok {
+				keys = append(keys, key)
+				refTypes[key] = refType
+				versions[key] = s.versions.get(refType)
+			}
+			grouped[key] = append(grouped[key], item)
+		}
+	}
+	s.mu.RUnlock()
+	if len(keys) == 0 {
+		return status.Error(codes.NotFound, fmt.Sprintf("%s was not found", query.Name))
+	}
+
+	summaries := make([]*pb.LikesSummary, 0, len(keys))
+	for _, key := range keys {
+		summary := s.summarize(refTypes[key], grouped[key])
+		summary.Version = versions[key]
+		summaries = append(summaries, summary)
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		switch query.OrderBy {
+		case pb.RankQuery_SCORE:
+			return summaries[i].Score > summaries[j].Score
+		case pb.RankQuery_RATIO:
+			return summaries[i].LikeRatio > summaries[j].LikeRatio
+		default:
+			return summaries[i].Total > summaries[j].Total
+		}
+	})
+	if query.Limit > 0 && int(query.Limit) < len(summaries) {
+		summaries = summaries[:query.Limit]
+	}
+
+	elapsedTime := uint64(time.Now().Sub(startTime))
+	for _, summary := range summaries {
+		summary.ElapsedTime = elapsedTime
+		if err := stream.Send(summary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateLike creates a like for a RefType and records it in the event log.
+// The deleted_at of the like is ignored.
+func (s *beerLikesServer) CreateLike(ctx context.Context, like *pb.Like) (*pb.Like, error) {
+	like, err := newLike(like)
+	if err != nil {
+		return &pb.Like{}, err
+	}
+	like.DeletedAt = nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findLike(like.Id) != nil {
+		return &pb.Like{}, status.Error(codes.AlreadyExists, fmt.Sprintf("%s already exists", like.Id))
+	}
+	if err := s.record(ctx, pb.LikeEvent_LIKE, like); err != nil {
+		return &pb.Like{}, status.Error(codes.Internal, fmt.Sprintf("failed to record %s: %v", like.Id, err))
+	}
+	grpc.SetHeader(ctx, versionMetadata(s.versions.get(like.RefType)))
+	return like, nil
+}
+
+// newLike validates a like to be stored and returns a copy of it at version 1,
+// with the id, created_at and reaction filled in when they are not set.
+func newLike(like *pb.Like) (*pb.Like, error) {
+	if like.RefType == nil || like.RefType.Name == "" || like.RefType.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "ref_type name and id are required")
+	}
+	like = proto.Clone(like).(*pb.Like)
+	if err := normalizeReaction(like); err != nil {
+		return nil, err
+	}
+	if like.Id == "" {
+		like.Id = newID()
+	}
+	if like.CreatedAt == nil {
+		like.CreatedAt = ptypes.TimestampNow()
+	}
+	like.Version = 1
+	return like, nil
+}
+
+// DeleteLike marks the like with the given id as deleted, bumps its version
+// and records it in the event log. The deleted like is kept until it is purged.
+func (s *beerLikesServer) DeleteLike(ctx context.Context, query *pb.LikeQuery) (*pb.Like, error) {
+	if query.Id == "" {
+		return &pb.Like{}, status.Error(codes.InvalidArgument, "id is required")
+	}
+	if err := validateMask("read_mask", query.ReadMask, readPaths); err != nil {
+		return &pb.Like{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := s.findLike(query.Id)
+	if current == nil || current.DeletedAt != nil {
+		return &pb.Like{}, status.Error(codes.NotFound, fmt.Sprintf("%s was not found", query.Id))
+	}
+	if err := checkVersion(current, query.ExpectedVersion); err != nil {
+		return &pb.Like{}, err
+	}
+	like := proto.Clone(current).(*pb.Like)
+	like.DeletedAt = ptypes.TimestampNow()
+	like.Version = current.Version + 1
+	if err := s.record(ctx, pb.LikeEvent_UNLIKE, like); err != nil {
+		return &pb.Like{}, status.Error(codes.Internal, fmt.Sprintf("failed to record %s: %v", like.Id, err))
+	}
+	grpc.SetHeader(ctx, versionMetadata(s.versions.get(like.RefType)))
+	return maskLike(like, query.ReadMask), nil
+}
+
+// UndeleteLike restores a deleted like, bumps its version and records it in
+// the event log.
+func (s *beerLikesServer) UndeleteLike(ctx context.Context, query *pb.LikeQuery) (*pb.Like, error) {
+	if query.Id == "" {
+		return &pb.Like{}, status.Error(codes.InvalidArgument, "id is required")
+	}
+	if err := validateMask("read_mask", query.ReadMask, readPaths); err != nil {
+		return &pb.Like{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := s.findLike(query.Id)
+	if current == nil {
+		return &pb.Like{}, status.Error(codes.NotFound, fmt.Sprintf("%s was not found", query.Id))
+	}
+	if current.DeletedAt == nil {
+		return &pb.Like{}, status.Error(codes.FailedPrecondition, fmt.Sprintf("%s is not deleted", query.Id))
+	}
+	if err := checkVersion(current, query.ExpectedVersion); err != nil {
+		return &pb.Like{}, err
+	}
+	like := proto.Clone(current).(*pb.Like)
+	like.DeletedAt = nil
+	like.Version = current.Version + 1
+	if err := s.record(ctx, pb.LikeEvent_LIKE, like); err != nil {
+		return &pb.Like{}, status.Error(codes.Internal, fmt.Sprintf("failed to record %s: %v", like.Id, err))
+	}
+	grpc.SetHeader(ctx, versionMetadata(s.versions.get(like.RefType)))
+	return maskLike(like, query.ReadMask), nil
+}
+
+// UpdateLike changes whether a like is a like or a dislike, or its reaction
+// and value if a reaction is given, and its RefType if one is given, and bumps
+// its version. With an update_mask only the fields in the mask are changed.
+// Changing liked without a reaction turns any reaction into a like or dislike,
+// so liked and the reaction never disagree.
+func (s *beerLikesServer) UpdateLike(ctx context.Context, req *pb.UpdateLikeRequest) (*pb.Like, error) {
+	if req.Like == nil || req.Like.Id == "" {
+		return &pb.Like{}, status.Error(codes.InvalidArgument, "like.id is required")
+	}
+	if refType := req.Like.RefType; refType != nil && (refType.Name == "" || refType.Id == "") {
+		return &pb.Like{}, status.Error(codes.InvalidArgument, "ref_type name and id are required")
+	}
+	if err := validateMask("update_mask", req.UpdateMask, updatePaths); err != nil {
+		return &pb.Like{}, err
+	}
+	if hasPath(req.UpdateMask, "ref_type") && req.Like.RefType == nil {
+		return &pb.Like{}, status.Error(codes.InvalidArgument, "ref_type is required by the update_mask")
+	}
+	if err := validateMask("read_mask", req.ReadMask, readPaths); err != nil {
+		return &pb.Like{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := s.findLike(req.Like.Id)
+	if current == nil || current.DeletedAt != nil {
+		return &pb.Like{}, status.Error(codes.NotFound, fmt.Sprintf("%s was not found", req.Like.Id))
+	}
+	if err := checkVersion(current, req.ExpectedVersion); err != nil {
+		return &pb.Like{}, err
+	}
+	like := proto.Clone(current).(*pb.Like)
+	mask := req.UpdateMask
+	if len(mask.GetPaths()) == 0 {
+		like.Liked = req.Like.Liked
+		if req.Like.Reaction != "" {
+			like.Reaction, like.Value = req.Like.Reaction, req.Like.Value
+		} else {
+			// Without a reaction the like becomes the like or dislike of liked.
+			like.Reaction, like.Value = "", 0
+		}
+		if req.Like.RefType != nil {
+			like.RefType = req.Like.RefType
+		}
+	} else {
+		if hasPath(mask, "liked") {
+			like.Liked = req.Like.Liked
+			if !hasPath(mask, "reaction") {
+				like.Reaction, like.Value = "", 0
+			}
+		}
+		if hasPath(mask, "reaction") {
+			like.Reaction = req.Like.Reaction
+		}
+		if hasPath(mask, "value") {
+			like.Value = req.Like.Value
+		}
+		if hasPath(mask, "ref_type") {
+			like.RefType = req.Like.RefType
+		}
+	}
+	if err := normalizeReaction(like); err != nil {
+		return &pb.Like{}, err
+	}
+	like.Version = current.Version + 1
+	event := &pb.LikeEvent{Type: pb.LikeEvent_UPDATE, Time: ptypes.TimestampNow(), Like: like}
+	if !proto.Equal(current.RefType, like.RefType) {
+		event.PreviousRefType = current.RefType
+	}
+	if err := s.recordEvent(ctx, event); err != nil {
+		return &pb.Like{}, status.Error(codes.Internal, fmt.Sprintf("failed to record %s: %v", like.Id, err))
+	}
+	grpc.SetHeader(ctx, versionMetadata(s.versions.get(like.RefType)))
+	return maskLike(like, req.ReadMask), nil
+}
+
+// ToggleLike creates, flips or deletes the like or dislike of a user for a
+// RefType to reach the target state, under the write lock so concurrent
+// toggles do not race. Other reactions of the user are left alone. The like is returned with the counts of the RefType after the change.
+func (s *beerLikesServer) ToggleLike(ctx context.Context, req *pb.ToggleLikeRequest) (*pb.ToggleLikeResponse, error) {
+	if req.UserId == "" {
+		return &pb.ToggleLikeResponse{}, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.RefType == nil || req.RefType.Name == "" || req.RefType.Id == "" {
+		return &pb.ToggleLikeResponse{}, status.Error(codes.InvalidArgument, "ref_type name and id are required")
+	}
+	if _, ok := pb.ToggleLikeRequest_Target_name[int32(req.Target)]; !ok {
+		return &pb.ToggleLikeResponse{}, status.Error(codes.InvalidArgument, fmt.Sprintf("unknown target: %v", req.Target))
+	}
+	if err := validateMask("read_mask", req.ReadMask, readPaths); err != nil {
+		return &pb.ToggleLikeResponse{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var current *pb.Like
+	for _, item := range s.index.refType(req.RefType) {
+		if item.UserId == req.UserId && item.DeletedAt == nil && isVote(item) {
+			current = item
+		}
+	}
+	target := req.Target
+	if target == pb.ToggleLikeRequest_TOGGLE {
+		if current != nil && current.Liked {
+			target = pb.ToggleLikeRequest_CLEARED
+		} else {
+			target = pb.ToggleLikeRequest_LIKED
+		}
+	}
+
+	like := current
+	eventType := pb.LikeEvent_UPDATE
+	switch {
+	case target == pb.ToggleLikeRequest_CLEARED && current == nil:
+		like = &pb.Like{}
+	case target == pb.ToggleLikeRequest_CLEARED:
+		like = proto.Clone(current).(*pb.Like)
+		like.DeletedAt = ptypes.TimestampNow()
+		like.Version = current.Version + 1
+		eventType = pb.LikeEvent_UNLIKE
+	case current == nil:
+		like, _ = newLike(&pb.Like{RefType: req.RefType, UserId: req.UserId, Liked: target == pb.ToggleLikeRequest_LIKED})
+		eventType = pb.LikeEvent_LIKE
+	case current.Liked != (target == pb.ToggleLikeRequest_LIKED):
+		like = proto.Clone(current).(*pb.Like)
+		like.Liked = !current.Liked
+		like.Reaction = voteReaction(like.Liked)
+		like.Version = current.Version + 1
+	}
+	if like != current && like.Id != "" {
+		if err := s.record(ctx, eventType, like); err != nil {
+			return &pb.ToggleLikeResponse{}, status.Error(codes.Internal, fmt.Sprintf("failed to record %s: %v", like.Id, err))
+		}
+	}
+
+	summary := s.summarize(req.RefType, s.index.refType(req.RefType))
+	summary.Version = s.versions.get(req.RefType)
+	grpc.SetHeader(ctx, versionMetadata(summary.Version))
+	return &pb.ToggleLikeResponse{Like: maskLike(like, req.ReadMask), Summary: summary}, nil
+}
+
+// checkVersion returns an Aborted error unless the like is at the expected
+// version, or expected is 0.
+func checkVersion(like *pb.Like, expected uint64) error {
+	if expected != 0 && expected != like.Version {
+		return status.Error(codes.Aborted, fmt.Sprintf("%s is at version %d, not %d", like.Id, like.Version, expected))
+	}
+	return nil
+}
+
+// findLike returns the like with the given id, or nil. Callers must hold s.mu.
+func (s *beerLikesServer) findLike(id string) *pb.Like {
+	if i, ok := s.positions[id]; ok {
+		return s.savedLikes[i]
+	}
+	return nil
+}
+
+// save stores a like, replacing the like with the same id in its position, and
+// indexes it. Callers must hold s.mu.
+func (s *beerLikesServer) save(like *pb.Like) {
+	if i, ok := s.positions[like.Id]; ok {
+		current := s.savedLikes[i]
+		if current.DeletedAt == nil {
+			s.rollups.remove(current)
+		}
+		s.index.replace(current, like)
+		s.savedLikes[i] = like
+	} else {
+		s.positions[like.Id] = len(s.savedLikes)
+		s.savedLikes = append(s.savedLikes, like)
+		s.index.add(like)
+	}
+	if like.DeletedAt == nil {
+		s.rollups.add(like)
+	}
+}
+
+// unsave removes the like with the given id and returns it, or nil. The likes
+// saved after it move up, so this takes time linear in the number of likes.
+// Callers must hold s.mu.
+func (s *beerLikesServer) unsave(id string) *pb.Like {
+	i, ok := s.positions[id]
+	if !ok {
+		return nil
+	}
+	like := s.savedLikes[i]
+	if like.DeletedAt == nil {
+		s.rollups.remove(like)
+	}
+	s.index.remove(like)
+	delete(s.positions, id)
+	s.savedLikes = append(s.savedLikes[:i], s.savedLikes[i+1:]...)
+	for _, item := range s.savedLikes[i:] {
+		s.positions[item.Id]--
+	}
+	return like
+}
+
+// summarize calculates the counts and scores for the likes of a RefType.
+// Deleted likes are only counted in deleted_count.
+func (s *beerLikesServer) summarize(refType *pb.RefType, likes []*pb.Like) *pb.LikesSummary {
+	summary := &pb.LikesSummary{RefType: refType}
+	var ratings int64
+	for _, item := range likes {
+		if item.DeletedAt != nil {
+			summary.DeletedCount++
+			continue
+		}
+		if summary.ReactionCounts == nil {
+			summary.ReactionCounts = make(map[string]int32)
+		}
+		summary.ReactionCounts[item.Reaction]++
+		switch item.Reaction {
+		case reactionLike:
+			summary.LikeCount++
+		case reactionDislike:
+			summary.DislikeCount++
+		case reactionRating:
+			summary.RatingCount++
+			ratings += int64(item.Value)
+		}
+	}
+	if summary.RatingCount > 0 {
+		summary.AverageRating = float64(ratings) / float64(summary.RatingCount)
+	}
+	summary.Total = summary.LikeCount - summary.DislikeCount
+	if n := summary.LikeCount + summary.DislikeCount; n > 0 {
+		summary.LikeRatio = float64(summary.LikeCount) / float64(n)
+	}
+	summary.Score = wilsonScore(summary.LikeCount, summary.DislikeCount, s.z)
+	return summary
+}
+
+// wilsonScore returns the lower bound of the Wilson score interval for the
+// proportion of positive ratings, so that a RefType with few votes is not
+// ranked above one with many votes and a similar ratio.
+func wilsonScore(positive, negative int32, z float64) float64 {
+	n := float64(positive + negative)
+	if n == 0 {
+		return 0
+	}
+	phat := float64(positive) / n
+	z2 := z * z
+	return (phat + z2/(2*n) - z*math.Sqrt((phat*(1-phat)+z2/(4*n))/n)) / (1 + z2/n)
+}
+
+// zScore returns the two-sided standard normal quantile for a confidence level.
+func zScore(confidence float64) float64 {
+	return math.Sqrt2 * math.Erfinv(confidence)
 }
 
 // loadLikes loads likes from a JSON file.
@@ -129,23 +661,107 @@ func (s *beerLikesServer) loadLikes(filePath string) {
 	if err != nil {
 		log.Warnf("Failed to load default likes: %v", err)
 	}
-	if err := json.Unmarshal(file, &s.savedLikes); err != nil {
+	// Decode each like with jsonpb so timestamps can be RFC 3339 strings.
+	var items []json.RawMessage
+	if err := json.Unmarshal(file, &items); err != nil {
 		log.Warnf("Failed to load default likes: %v", err)
 	}
+	for _, item := range items {
+		like := &pb.Like{}
+		if err := jsonpb.UnmarshalString(string(item), like); err != nil {
+			log.Warnf("Failed to load default like: %v", err)
+			continue
+		}
+		if like.Version == 0 {
+			like.Version = 1
+		}
+		if like.Reaction == "" {
+			like.Reaction = voteReaction(like.Liked)
+		}
+		s.save(like)
+	}
 }
 
 func serialize(Like *pb.Like) string {
-	return fmt.Sprintf("%d %d", Like.RefType, Like.Id)
+	return fmt.Sprintf("%s %s", refTypeKey(Like.RefType), Like.Id)
+}
+
+// refTypeKey returns a comparable key for a RefType. The name is prefixed with
+// its length, so a / in a name or id cannot make two RefTypes share a key.
+func refTypeKey(refType *pb.RefType) string {
+	return fmt.Sprintf("%d:%s/%s", len(refType.GetName()), refType.GetName(), refType.GetId())
+}
+
+// refTypeString returns a RefType as name/id for messages.
+func refTypeString(refType *pb.RefType) string {
+	return refType.GetName() + "/" + refType.GetId()
+}
+
+// newID returns a random (version 4) UUID for a new like.
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("Failed to generate an id: %v", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
 }
 
-func newServer() *beerLikesServer {
-	s := &beerLikesServer{}
-	s.loadLikes(*jsonDBFile)
+// newServer returns the server of a tenant with the likes of its files.
+func newServer(tenant string) *beerLikesServer {
+	s := &beerLikesServer{tenant: tenant, z: zScore(*confidence), positions: make(map[string]int), rollups: newRollups(), index: newLikeIndex(), versions: newVersions(), refTypes: newRefTypeTree(), summaries: newSummaryCache(), watchers: newWatchers()}
+	dbFile, logFile := tenantPath(*jsonDBFile, tenant), tenantPath(*eventLogFile, tenant)
+	if logFile == "" {
+		s.loadLikes(dbFile)
+		return s
+	}
+	policy, err := parseSyncPolicy(*fsync)
+	if err != nil {
+		log.Fatalf("Failed to open the event log: %v", err)
+	}
+	sequence, err := s.replay(logFile, dbFile)
+	if err != nil {
+		log.Fatalf("Failed to replay the event log of tenant %s: %v", tenant, err)
+	}
+	s.events, err = openEventLog(logFile, sequence, policy, *fsyncInterval)
+	if err != nil {
+		log.Fatalf("Failed to open the event log of tenant %s: %v", tenant, err)
+	}
+	if *snapshotInterval > 0 {
+		go s.snapshotLoop(*snapshotInterval)
+	}
 	return s
 }
 
 func defaultServerOpts() []grpc.ServerOption {
-	return []grpc.ServerOption{}
+	opts := []grpc.ServerOption{grpc.MaxRecvMsgSize(*maxRecvMsgSize)}
+	if *maxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(uint32(*maxConcurrentStreams)))
+	}
+	return opts
+}
+
+// serverCredentials returns the TLS credentials of the server. With a client
+// CA file the clients may present a certificate, which is verified with it.
+func serverCredentials(certFile, keyFile, clientCAFile string) (credentials.TransportCredentials, error) {
+	if clientCAFile == "" {
+		return credentials.NewServerTLSFromFile(certFile, keyFile)
+	}
+	cert, err := cryptotls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no CA certificates in %s", clientCAFile)
+	}
+	// Clients without a certificate can still use the default tenant.
+	return credentials.NewTLS(&cryptotls.Config{Certificates: []cryptotls.Certificate{cert}, ClientCAs: pool, ClientAuth: cryptotls.VerifyClientCertIfGiven}), nil
 }
 
 // withDuration returns the duration of a grpc connection in nanoseconds
@@ -155,6 +771,9 @@ func withDuration(duration time.Duration) (key string, value interface{}) {
 
 func main() {
 	flag.Parse()
+	if err := loadConfig(); err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
 	host_port := fmt.Sprintf("%s:%d", *host, *port)
 	lis, err := net.Listen("tcp", host_port)
 	if err != nil {
@@ -162,26 +781,73 @@ func main() {
 	}
 
 	var opts []grpc.ServerOption
+	if *tls {
+		creds, err := serverCredentials(*certFile, *keyFile, *clientCA)
+		if err != nil {
+			log.Fatalf("Failed to generate credentials %v", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
 
 	logrusEntry := log.NewEntry(log.StandardLogger())
+	sampling, _ := parseLogSampling(*logSampling)
 	logOpts := []grpc_logrus.Option{
 		grpc_logrus.WithDurationField(withDuration),
+		grpc_logrus.WithDecider(logSampler(sampling)),
+	}
+	go toggleDebugLogging()
+
+	tenantNames, err := parseTenants(*tenants)
+	if err != nil {
+		log.Fatalf("Invalid -tenants: %v", err)
+	}
+	audit, err := newAuditor(*auditSinks, *auditLogFile)
+	if err != nil {
+		log.Fatalf("Failed to open the audit sinks: %v", err)
+	}
+	principals, err := parseTenantPrincipals(*tenantPrincipals, tenantNames)
+	if err != nil {
+		log.Fatalf("Invalid -tenant_principals: %v", err)
+	}
+	t := newTenantServer(tenantNames, principals, parsePrincipals(*adminPrincipals), audit)
+
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		grpc_ctxtags.UnaryServerInterceptor(),
+		requestIDUnaryInterceptor,
+		grpc_logrus.UnaryServerInterceptor(logrusEntry, logOpts...),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		grpc_ctxtags.StreamServerInterceptor(),
+		requestIDStreamInterceptor,
+		grpc_logrus.StreamServerInterceptor(logrusEntry, logOpts...),
+	}
+	// The writes of unknown tenants are audited too.
+	if audit != nil {
+		unaryInterceptors = append(unaryInterceptors, audit.unaryInterceptor)
+		streamInterceptors = append(streamInterceptors, audit.streamInterceptor)
+	}
+	unaryInterceptors = append(unaryInterceptors, t.unaryInterceptor)
+	streamInterceptors = append(streamInterceptors, t.streamInterceptor)
+	if *idempotencyWindow > 0 {
+		unaryInterceptors = append(unaryInterceptors, newIdempotencyStore(*idempotencyWindow).unaryInterceptor)
 	}
 
 	opts = append(
 		opts,
-		grpc_middleware.WithUnaryServerChain(
-			grpc_ctxtags.UnaryServerInterceptor(),
-			grpc_logrus.UnaryServerInterceptor(logrusEntry, logOpts...)),
-		grpc_middleware.WithStreamServerChain(
-			grpc_ctxtags.StreamServerInterceptor(),
-			grpc_logrus.StreamServerInterceptor(logrusEntry, logOpts...)),
+		grpc_middleware.WithUnaryServerChain(unaryInterceptors...),
+		grpc_middleware.WithStreamServerChain(streamInterceptors...),
 	)
 
 	log.Infof("Starting grpc server on %s", host_port)
 	grpcServer := grpc.NewServer(append(defaultServerOpts(), opts...)...)
 
-	pb.RegisterBeerLikesServer(grpcServer, newServer())
+	if *tombstoneRetention > 0 && *compactionInterval > 0 {
+		for _, s := range t.servers {
+			go s.compactLoop(*tombstoneRetention, *compactionInterval)
+		}
+	}
+	pb.RegisterBeerLikesServer(grpcServer, t)
+	pb.RegisterBeerLikesAdminServer(grpcServer, t)
 	grpcServer.Serve(lis)
 	log.Infof("Stopping grpc server...")
 }
@@ -0,0 +1,249 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// createTestLike creates a like, of beer 1 unless it has a RefType, and
+// returns it.
+func createTestLike(t *testing.T, s *beerLikesServer, like *pb.Like) *pb.Like {
+	if like.RefType == nil {
+		like.RefType = &pb.RefType{Name: "beer", Id: "1"}
+	}
+	created, err := s.CreateLike(context.Background(), like)
+	if err != nil {
+		t.Fatalf("CreateLike(%v): %v", like, err)
+	}
+	return created
+}
+
+func TestStaleExpectedVersionIsAborted(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer()
+	like := createTestLike(t, s, &pb.Like{Liked: true})
+	updated, err := s.UpdateLike(ctx, &pb.UpdateLikeRequest{Like: &pb.Like{Id: like.Id, Liked: false}, ExpectedVersion: 1})
+	if err != nil {
+		t.Fatalf("UpdateLike at the current version: %v", err)
+	}
+	if updated.Version != 2 {
+		t.Fatalf("UpdateLike returned version %d, want 2", updated.Version)
+	}
+	current := proto.Clone(s.findLike(like.Id))
+
+	if _, err := s.UpdateLike(ctx, &pb.UpdateLikeRequest{Like: &pb.Like{Id: like.Id, Liked: true}, ExpectedVersion: 1}); status.Code(err) != codes.Aborted {
+		t.Errorf("UpdateLike at a stale version returned %v, want Aborted", err)
+	}
+	if _, err := s.DeleteLike(ctx, &pb.LikeQuery{Id: like.Id, ExpectedVersion: 1}); status.Code(err) != codes.Aborted {
+		t.Errorf("DeleteLike at a stale version returned %v, want Aborted", err)
+	}
+	if like := s.findLike(like.Id); !proto.Equal(like, current) {
+		t.Errorf("the like is %v after the aborted writes, want it unchanged at %v", like, current)
+	}
+
+	if _, err := s.DeleteLike(ctx, &pb.LikeQuery{Id: like.Id, ExpectedVersion: 2}); err != nil {
+		t.Errorf("DeleteLike at the current version: %v", err)
+	}
+}
+
+// likesStream collects the likes sent by ListLikes.
+type likesStream struct {
+	grpc.ServerStream
+	likes []*pb.Like
+}
+
+func (s *likesStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *likesStream) SetHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *likesStream) Send(like *pb.Like) error {
+	s.likes = append(s.likes, like)
+	return nil
+}
+
+// visibleLikes returns the number of likes of beer 1 returned by GetLike,
+// ListLikes and GetLikesSummary, with or without the deleted likes.
+func visibleLikes(t *testing.T, s *beerLikesServer, id string, includeDeleted bool) (int, int, int) {
+	beer := &pb.RefType{Name: "beer", Id: "1"}
+	got := 0
+	if _, err := s.GetLike(context.Background(), &pb.LikeQuery{Id: id, IncludeDeleted: includeDeleted}); err == nil {
+		got = 1
+	} else if status.Code(err) != codes.NotFound {
+		t.Fatal(err)
+	}
+	stream := &likesStream{}
+	if err := s.ListLikes(&pb.LikesQuery{RefType: beer, IncludeDeleted: includeDeleted}, stream); err != nil && status.Code(err) != codes.NotFound {
+		t.Fatal(err)
+	}
+	summary, err := s.GetLikesSummary(context.Background(), &pb.LikesQuery{RefType: beer, IncludeDeleted: includeDeleted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return got, len(stream.likes), len(summary.Likes)
+}
+
+func TestDeletedLikesAreHiddenUntilUndeleted(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer()
+	createTestLike(t, s, &pb.Like{Liked: true})
+	like := createTestLike(t, s, &pb.Like{Liked: true})
+	if _, err := s.DeleteLike(ctx, &pb.LikeQuery{Id: like.Id}); err != nil {
+		t.Fatal(err)
+	}
+	if get, list, summary := visibleLikes(t, s, like.Id, false); get != 0 || list != 1 || summary != 1 {
+		t.Errorf("GetLike, ListLikes and GetLikesSummary return %d, %d and %d likes after a delete, want 0, 1 and 1", get, list, summary)
+	}
+	if get, list, summary := visibleLikes(t, s, like.Id, true); get != 1 || list != 2 || summary != 2 {
+		t.Errorf("with include_deleted GetLike, ListLikes and GetLikesSummary return %d, %d and %d likes, want 1, 2 and 2", get, list, summary)
+	}
+	summary, err := s.GetLikesSummary(ctx, &pb.LikesQuery{RefType: like.RefType})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.LikeCount != 1 || summary.DeletedCount != 1 {
+		t.Errorf("the summary counts %d likes and %d deleted, want 1 and 1", summary.LikeCount, summary.DeletedCount)
+	}
+
+	restored, err := s.UndeleteLike(ctx, &pb.LikeQuery{Id: like.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.DeletedAt != nil || restored.Version != 3 {
+		t.Errorf("UndeleteLike returned %v, want it at version 3 without deleted_at", restored)
+	}
+	if get, list, summary := visibleLikes(t, s, like.Id, false); get != 1 || list != 2 || summary != 2 {
+		t.Errorf("GetLike, ListLikes and GetLikesSummary return %d, %d and %d likes after an undelete, want 1, 2 and 2", get, list, summary)
+	}
+	if _, err := s.UndeleteLike(ctx, &pb.LikeQuery{Id: like.Id}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("UndeleteLike of a like that is not deleted returned %v, want FailedPrecondition", err)
+	}
+}
+
+// toggleState returns the state of a like returned by ToggleLike: none,
+// liked or disliked.
+func toggleState(like *pb.Like) string {
+	switch {
+	case like.GetId() == "" || like.DeletedAt != nil:
+		return "none"
+	case like.Liked:
+		return "liked"
+	default:
+		return "disliked"
+	}
+}
+
+func TestToggleLikeTransitions(t *testing.T) {
+	tests := []struct {
+		from    string
+		target  pb.ToggleLikeRequest_Target
+		want    string
+		changed bool
+	}{
+		{"none", pb.ToggleLikeRequest_TOGGLE, "liked", true},
+		{"none", pb.ToggleLikeRequest_LIKED, "liked", true},
+		{"none", pb.ToggleLikeRequest_DISLIKED, "disliked", true},
+		{"none", pb.ToggleLikeRequest_CLEARED, "none", false},
+		{"liked", pb.ToggleLikeRequest_TOGGLE, "none", true},
+		{"liked", pb.ToggleLikeRequest_LIKED, "liked", false},
+		{"liked", pb.ToggleLikeRequest_DISLIKED, "disliked", true},
+		{"liked", pb.ToggleLikeRequest_CLEARED, "none", true},
+		{"disliked", pb.ToggleLikeRequest_TOGGLE, "liked", true},
+		{"disliked", pb.ToggleLikeRequest_LIKED, "liked", true},
+		{"disliked", pb.ToggleLikeRequest_DISLIKED, "disliked", false},
+		{"disliked", pb.ToggleLikeRequest_CLEARED, "none", true},
+	}
+	beer := &pb.RefType{Name: "beer", Id: "1"}
+	for _, test := range tests {
+		s := newTestServer()
+		// Another user's like and reaction of the user are left alone.
+		createTestLike(t, s, &pb.Like{UserId: "u2", Liked: true})
+		createTestLike(t, s, &pb.Like{UserId: "u1", Reaction: "cheers"})
+		var current *pb.Like
+		if test.from != "none" {
+			current = createTestLike(t, s, &pb.Like{UserId: "u1", Liked: test.from == "liked"})
+		}
+		version := s.versions.get(beer)
+
+		resp, err := s.ToggleLike(context.Background(), &pb.ToggleLikeRequest{UserId: "u1", RefType: beer, Target: test.target})
+		if err != nil {
+			t.Errorf("%s %v: ToggleLike returned %v", test.from, test.target, err)
+			continue
+		}
+		if state := toggleState(resp.Like); state != test.want {
+			t.Errorf("%s %v: ToggleLike returned a %s like %v, want %s", test.from, test.target, state, resp.Like, test.want)
+		}
+		if current != nil && resp.Like.Id != current.Id {
+			t.Errorf("%s %v: ToggleLike returned the like %s, want the like of the user %s", test.from, test.target, resp.Like.Id, current.Id)
+		}
+		if changed := s.versions.get(beer) != version; changed != test.changed {
+			t.Errorf("%s %v: ToggleLike changed the likes: %v, want %v", test.from, test.target, changed, test.changed)
+		}
+		if current != nil && test.changed && resp.Like.Version != current.Version+1 {
+			t.Errorf("%s %v: ToggleLike returned version %d, want %d", test.from, test.target, resp.Like.Version, current.Version+1)
+		}
+		likes, dislikes := int32(1), int32(0)
+		switch test.want {
+		case "liked":
+			likes++
+		case "disliked":
+			dislikes++
+		}
+		if resp.Summary.LikeCount != likes || resp.Summary.DislikeCount != dislikes || resp.Summary.ReactionCounts["cheers"] != 1 {
+			t.Errorf("%s %v: the summary counts %d likes, %d dislikes and %d cheers, want %d, %d and 1", test.from, test.target, resp.Summary.LikeCount, resp.Summary.DislikeCount, resp.Summary.ReactionCounts["cheers"], likes, dislikes)
+		}
+	}
+}
+
+func TestUpdateLikeChangingLikedWithoutAReaction(t *testing.T) {
+	tests := []struct {
+		name     string
+		like     *pb.Like
+		mask     []string
+		liked    bool
+		reaction string
+		value    int32
+	}{
+		{"like to dislike", &pb.Like{Liked: true}, nil, false, reactionDislike, 0},
+		{"rating to like", &pb.Like{Reaction: reactionRating, Value: 4}, nil, true, reactionLike, 0},
+		{"custom to dislike", &pb.Like{Reaction: "cheers", Liked: true}, nil, false, reactionDislike, 0},
+		{"rating to like by mask", &pb.Like{Reaction: reactionRating, Value: 4}, []string{"liked"}, true, reactionLike, 0},
+		{"custom to dislike by mask", &pb.Like{Reaction: "cheers", Liked: true}, []string{"liked"}, false, reactionDislike, 0},
+	}
+	for _, test := range tests {
+		s := newTestServer()
+		like := createTestLike(t, s, test.like)
+		req := &pb.UpdateLikeRequest{Like: &pb.Like{Id: like.Id, Liked: test.liked}}
+		if test.mask != nil {
+			req.UpdateMask = &field_mask.FieldMask{Paths: test.mask}
+		}
+		updated, err := s.UpdateLike(context.Background(), req)
+		if err != nil {
+			t.Errorf("%s: UpdateLike returned %v", test.name, err)
+			continue
+		}
+		for _, got := range []*pb.Like{updated, s.findLike(like.Id)} {
+			if got.Liked != test.liked || got.Reaction != test.reaction || got.Value != test.value {
+				t.Errorf("%s: the like is liked %v, %q and %d, want %v, %q and %d", test.name, got.Liked, got.Reaction, got.Value, test.liked, test.reaction, test.value)
+			}
+		}
+	}
+}
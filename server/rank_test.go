@@ -0,0 +1,109 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+func TestZScore(t *testing.T) {
+	tests := []struct {
+		confidence float64
+		want       float64
+	}{
+		{0.8, 1.2815515655446004},
+		{0.95, 1.959963984540054},
+		{0.99, 2.5758293035489004},
+	}
+	for _, test := range tests {
+		if got := zScore(test.confidence); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("zScore(%v) = %v, want %v", test.confidence, got, test.want)
+		}
+	}
+}
+
+func TestWilsonScore(t *testing.T) {
+	z := zScore(0.95)
+	tests := []struct {
+		positive, negative int32
+		want               float64
+	}{
+		{0, 0, 0},
+		{0, 1, 0},
+		{1, 0, 0.2065493143772375},
+		{5, 1, 0.43649717781353},
+		{50, 10, 0.7196838683638548},
+	}
+	for _, test := range tests {
+		if got := wilsonScore(test.positive, test.negative, z); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("wilsonScore(%d, %d) = %v, want %v", test.positive, test.negative, got, test.want)
+		}
+	}
+}
+
+// rankStream collects the RefType ids of the summaries sent by RankLikes.
+type rankStream struct {
+	grpc.ServerStream
+	ids []string
+}
+
+func (s *rankStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *rankStream) Send(summary *pb.LikesSummary) error {
+	s.ids = append(s.ids, summary.RefType.Id)
+	return nil
+}
+
+func TestRankLikesOrdersByScore(t *testing.T) {
+	s := newTestServer()
+	s.z = zScore(0.95)
+	// few has the best ratio, but the fewest votes, and many has a lower
+	// total than most but more confidence in its ratio.
+	votes := []struct {
+		id                 string
+		positive, negative int
+	}{
+		{"few", 1, 0},
+		{"some", 5, 1},
+		{"many", 50, 10},
+		{"most", 60, 40},
+	}
+	for _, vote := range votes {
+		for i := 0; i < vote.positive+vote.negative; i++ {
+			like := &pb.Like{Id: fmt.Sprintf("%s-%d", vote.id, i), RefType: &pb.RefType{Name: "beer", Id: vote.id}, Liked: i < vote.positive}
+			if _, err := s.CreateLike(context.Background(), like); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	tests := []struct {
+		orderBy pb.RankQuery_OrderBy
+		want    []string
+	}{
+		{pb.RankQuery_SCORE, []string{"many", "most", "some", "few"}},
+		{pb.RankQuery_RATIO, []string{"few", "some", "many", "most"}},
+		{pb.RankQuery_TOTAL, []string{"many", "most", "some", "few"}},
+	}
+	for _, test := range tests {
+		stream := &rankStream{}
+		if err := s.RankLikes(&pb.RankQuery{Name: "beer", OrderBy: test.orderBy}, stream); err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(stream.ids) != fmt.Sprint(test.want) {
+			t.Errorf("RankLikes by %v returned %v, want %v", test.orderBy, stream.ids, test.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+	"github.com/phriscage/beer-likes/beerlikes/reaction"
+)
+
+const (
+	reactionLike    = reaction.Like
+	reactionDislike = reaction.Dislike
+	reactionRating  = reaction.Rating
+)
+
+// normalizeReaction validates the reaction of a like and keeps it consistent
+// with liked: an empty reaction is set from liked, and liked is set from a
+// like or dislike reaction.
+func normalizeReaction(like *pb.Like) error {
+	if err := reaction.Normalize(like); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+// voteReaction returns the reaction of a like or dislike.
+func voteReaction(liked bool) string {
+	return reaction.Vote(liked)
+}
+
+// isVote reports whether a like is a like or dislike rather than another reaction.
+func isVote(like *pb.Like) bool {
+	return reaction.IsVote(like.Reaction)
+}
@@ -0,0 +1,173 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// maxRefTypeDepth limits the number of ancestors of a RefType.
+const maxRefTypeDepth = 16
+
+// refTypeTree holds the parent of every RefType that has one, by refTypeKey.
+type refTypeTree struct {
+	parents map[string]*pb.RefTypeParent
+}
+
+func newRefTypeTree() *refTypeTree {
+	return &refTypeTree{parents: make(map[string]*pb.RefTypeParent)}
+}
+
+// parent returns the parent of a RefType, or nil.
+func (t *refTypeTree) parent(refType *pb.RefType) *pb.RefType {
+	if link, ok := t.parents[refTypeKey(refType)]; ok {
+		return link.Parent
+	}
+	return nil
+}
+
+// ancestors returns the parent of a RefType, its grandparent and so on.
+func (t *refTypeTree) ancestors(refType *pb.RefType) []*pb.RefType {
+	var ancestors []*pb.RefType
+	for parent := t.parent(refType); parent != nil && len(ancestors) < maxRefTypeDepth; parent = t.parent(parent) {
+		ancestors = append(ancestors, parent)
+	}
+	return ancestors
+}
+
+// within reports whether a RefType is the given ancestor or one of its descendants.
+func (t *refTypeTree) within(refType, ancestor *pb.RefType) bool {
+	key := refTypeKey(ancestor)
+	if refTypeKey(refType) == key {
+		return true
+	}
+	for _, parent := range t.ancestors(refType) {
+		if refTypeKey(parent) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// height returns the number of generations of descendants of a RefType.
+func (t *refTypeTree) height(refType *pb.RefType) int {
+	key := refTypeKey(refType)
+	height := 0
+	for _, link := range t.parents {
+		for generation, parent := range t.ancestors(link.RefType) {
+			if refTypeKey(parent) == key && generation+1 > height {
+				height = generation + 1
+			}
+		}
+	}
+	return height
+}
+
+// check returns an InvalidArgument error if the parent would make a cycle or
+// a hierarchy deeper than maxRefTypeDepth.
+func (t *refTypeTree) check(refType, parent *pb.RefType) error {
+	if parent == nil {
+		return nil
+	}
+	chain := append([]*pb.RefType{parent}, t.ancestors(parent)...)
+	for _, ancestor := range chain {
+		if refTypeKey(ancestor) == refTypeKey(refType) {
+			return status.Error(codes.InvalidArgument, fmt.Sprintf("%s cannot be a descendant of itself", refTypeString(refType)))
+		}
+	}
+	if len(chain)+t.height(refType) > maxRefTypeDepth {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("RefTypes cannot have more than %d ancestors", maxRefTypeDepth))
+	}
+	return nil
+}
+
+// set sets the parent of a RefType, or removes it if the parent is nil.
+func (t *refTypeTree) set(link *pb.RefTypeParent) {
+	if link.Parent == nil {
+		delete(t.parents, refTypeKey(link.RefType))
+		return
+	}
+	t.parents[refTypeKey(link.RefType)] = link
+}
+
+// list returns the parents of the RefTypes with the given name, or of every
+// RefType if it is empty, ordered by RefType.
+func (t *refTypeTree) list(name string) []*pb.RefTypeParent {
+	var keys []string
+	for key, link := range t.parents {
+		if name == "" || link.RefType.Name == name {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	parents := make([]*pb.RefTypeParent, len(keys))
+	for i, key := range keys {
+		parents[i] = t.parents[key]
+	}
+	return parents
+}
+
+// changed records a change to the likes of a RefType, which is also a change
+// to the rollups of its ancestors. Callers must hold s.mu.
+func (s *beerLikesServer) changed(refType *pb.RefType) {
+	for _, item := range append([]*pb.RefType{refType}, s.refTypes.ancestors(refType)...) {
+		s.versions.bump(item)
+		s.summaries.remove(item)
+	}
+}
+
+// applyParent sets the parent of a RefType. Callers must hold s.mu.
+func (s *beerLikesServer) applyParent(parent *pb.RefTypeParent) {
+	if parent.RefType == nil {
+		return
+	}
+	s.changed(parent.RefType)
+	s.refTypes.set(parent)
+	s.changed(parent.RefType)
+}
+
+// SetRefTypeParent sets or removes the parent of a RefType and records it in
+// the event log.
+func (s *beerLikesServer) SetRefTypeParent(ctx context.Context, req *pb.RefTypeParent) (*pb.RefTypeParent, error) {
+	if req.RefType == nil || req.RefType.Name == "" || req.RefType.Id == "" {
+		return &pb.RefTypeParent{}, status.Error(codes.InvalidArgument, "ref_type name and id are required")
+	}
+	if req.Parent != nil && (req.Parent.Name == "" || req.Parent.Id == "") {
+		return &pb.RefTypeParent{}, status.Error(codes.InvalidArgument, "parent name and id are required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.refTypes.check(req.RefType, req.Parent); err != nil {
+		return &pb.RefTypeParent{}, err
+	}
+	event := &pb.LikeEvent{Type: pb.LikeEvent_PARENT, Time: ptypes.TimestampNow(), Parent: req}
+	if err := s.recordEvent(ctx, event); err != nil {
+		return &pb.RefTypeParent{}, status.Error(codes.Internal, fmt.Sprintf("failed to record the parent of %s: %v", refTypeString(req.RefType), err))
+	}
+	return req, nil
+}
+
+// ListRefTypeParents streams the parents of the RefTypes with the given name.
+func (s *beerLikesServer) ListRefTypeParents(query *pb.RefTypeParentsQuery, stream pb.BeerLikesAdmin_ListRefTypeParentsServer) error {
+	s.mu.RLock()
+	parents := s.refTypes.list(query.Name)
+	s.mu.RUnlock()
+	for _, parent := range parents {
+		if err := stream.Send(parent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// chainRefTypes returns n RefTypes where each is the parent of the one before.
+func chainRefTypes(t *testing.T, s *beerLikesServer, n int) []*pb.RefType {
+	var refTypes []*pb.RefType
+	for i := 0; i < n; i++ {
+		refTypes = append(refTypes, &pb.RefType{Name: "level", Id: fmt.Sprint(i)})
+		if i == 0 {
+			continue
+		}
+		if _, err := s.SetRefTypeParent(context.Background(), &pb.RefTypeParent{RefType: refTypes[i-1], Parent: refTypes[i]}); err != nil {
+			t.Fatalf("setting the parent of %v: %v", refTypes[i-1], err)
+		}
+	}
+	return refTypes
+}
+
+func TestSetRefTypeParentRejectsCycles(t *testing.T) {
+	s := newTestServer()
+	refTypes := chainRefTypes(t, s, 3)
+	tests := []struct {
+		name            string
+		refType, parent *pb.RefType
+	}{
+		{"itself", refTypes[0], refTypes[0]},
+		{"its child", refTypes[1], refTypes[0]},
+		{"its grandchild", refTypes[2], refTypes[0]},
+	}
+	for _, test := range tests {
+		_, err := s.SetRefTypeParent(context.Background(), &pb.RefTypeParent{RefType: test.refType, Parent: test.parent})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: SetRefTypeParent returned %v, want InvalidArgument", test.name, err)
+		}
+	}
+	if parent := s.refTypes.parent(refTypes[2]); parent != nil {
+		t.Errorf("the rejected parents left %v the parent of %v", parent, refTypes[2])
+	}
+}
+
+func TestSetRefTypeParentDepthLimit(t *testing.T) {
+	s := newTestServer()
+	refTypes := chainRefTypes(t, s, maxRefTypeDepth+1)
+	if n := len(s.refTypes.ancestors(refTypes[0])); n != maxRefTypeDepth {
+		t.Fatalf("the bottom RefType has %d ancestors, want %d", n, maxRefTypeDepth)
+	}
+	top := &pb.RefType{Name: "level", Id: "top"}
+	if _, err := s.SetRefTypeParent(context.Background(), &pb.RefTypeParent{RefType: refTypes[maxRefTypeDepth], Parent: top}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("a parent above %d ancestors returned %v, want InvalidArgument", maxRefTypeDepth, err)
+	}
+	bottom := &pb.RefType{Name: "level", Id: "bottom"}
+	if _, err := s.SetRefTypeParent(context.Background(), &pb.RefTypeParent{RefType: bottom, Parent: refTypes[0]}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("a child below %d ancestors returned %v, want InvalidArgument", maxRefTypeDepth, err)
+	}
+	// Moving the bottom RefType up keeps the hierarchy within the limit.
+	if _, err := s.SetRefTypeParent(context.Background(), &pb.RefTypeParent{RefType: refTypes[0], Parent: refTypes[2]}); err != nil {
+		t.Errorf("moving the bottom RefType up returned %v", err)
+	}
+}
+
+func TestSetRefTypeParentBumpsAncestorVersions(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer()
+	beer := &pb.RefType{Name: "beer", Id: "1"}
+	oldBrewery := &pb.RefType{Name: "brewery", Id: "1"}
+	newBrewery := &pb.RefType{Name: "brewery", Id: "2"}
+	if _, err := s.SetRefTypeParent(ctx, &pb.RefTypeParent{RefType: beer, Parent: oldBrewery}); err != nil {
+		t.Fatal(err)
+	}
+	createTestLike(t, s, &pb.Like{RefType: beer, Liked: true})
+	before := map[*pb.RefType]uint64{}
+	for _, refType := range []*pb.RefType{beer, oldBrewery, newBrewery} {
+		before[refType] = s.versions.get(refType)
+	}
+	if _, err := s.SetRefTypeParent(ctx, &pb.RefTypeParent{RefType: beer, Parent: newBrewery}); err != nil {
+		t.Fatal(err)
+	}
+	for _, refType := range []*pb.RefType{beer, oldBrewery, newBrewery} {
+		if version := s.versions.get(refType); version <= before[refType] {
+			t.Errorf("moving %v left the version of %v at %d", beer, refType, version)
+		}
+	}
+	for _, test := range []struct {
+		refType *pb.RefType
+		likes   int32
+	}{{oldBrewery, 0}, {newBrewery, 1}} {
+		summary, err := s.GetLikesSummary(ctx, &pb.LikesQuery{RefType: test.refType, Rollup: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary.LikeCount != test.likes {
+			t.Errorf("the rollup of %v counts %d likes, want %d", test.refType, summary.LikeCount, test.likes)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+const (
+	// tenantHeader is the request metadata key of the tenant of a call.
+	tenantHeader = "x-tenant-id"
+	// defaultTenant is the tenant of the calls without a tenantHeader. Its
+	// likes are stored in the files of the flags as they are.
+	defaultTenant = "default"
+)
+
+var tenantPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// tenantStats counts the requests of a tenant. It is updated atomically.
+type tenantStats struct {
+	requests uint64
+	errors   uint64
+}
+
+// tenantServer routes every call to the beerLikesServer of its tenant, so
+// each tenant has its own likes, event log, watchers and caches and a call
+// cannot see the likes of another tenant. A call may only use the tenant of
+// its verified principal, or the default tenant if the principal has none.
+type tenantServer struct {
+	servers    map[string]*beerLikesServer // by tenant, fixed at startup
+	stats      map[string]*tenantStats
+	principals map[string]string // tenant of each verified principal
+	admins     map[string]bool   // verified principals ListTenants shows every tenant to
+	audit      *auditor          // nil when auditing is disabled
+}
+
+// parseTenants validates a comma separated -tenants flag value and returns
+// the tenants with the default tenant first.
+func parseTenants(value string) ([]string, error) {
+	tenants := []string{defaultTenant}
+	seen := map[string]bool{defaultTenant: true}
+	for _, tenant := range strings.Split(value, ",") {
+		tenant = strings.TrimSpace(tenant)
+		if tenant == "" || seen[tenant] {
+			continue
+		}
+		if !tenantPattern.MatchString(tenant) {
+			return nil, fmt.Errorf("tenant %q must be up to 32 lower case letters, digits, underscores and dashes", tenant)
+		}
+		seen[tenant] = true
+		tenants = append(tenants, tenant)
+	}
+	return tenants, nil
+}
+
+// parseTenantPrincipals validates a comma separated -tenant_principals flag
+// value of principal=tenant pairs of the given tenants.
+func parseTenantPrincipals(value string, tenants []string) (map[string]string, error) {
+	known := make(map[string]bool)
+	for _, tenant := range tenants {
+		known[tenant] = true
+	}
+	principals := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("%q is not a principal=tenant pair", pair)
+		}
+		principal, tenant := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if !known[tenant] {
+			return nil, fmt.Errorf("principal %q has unknown tenant %q", principal, tenant)
+		}
+		if other, ok := principals[principal]; ok && other != tenant {
+			return nil, fmt.Errorf("principal %q has tenants %q and %q", principal, other, tenant)
+		}
+		principals[principal] = tenant
+	}
+	return principals, nil
+}
+
+// parsePrincipals returns the set of a comma separated list of principals.
+func parsePrincipals(value string) map[string]bool {
+	principals := make(map[string]bool)
+	for _, principal := range strings.Split(value, ",") {
+		if principal = strings.TrimSpace(principal); principal != "" {
+			principals[principal] = true
+		}
+	}
+	return principals
+}
+
+// tenantPath returns the file of a tenant next to the file of the default
+// tenant, e.g. beer_likes_db.wine.json for beer_likes_db.json.
+func tenantPath(path, tenant string) string {
+	if path == "" || tenant == defaultTenant {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + tenant + ext
+}
+
+// tenantName returns the tenant of a call from its metadata.
+func tenantName(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(tenantHeader); len(values) > 0 && values[0] != "" {
+		return values[0]
+	}
+	return defaultTenant
+}
+
+func newTenantServer(tenants []string, principals map[string]string, admins map[string]bool, audit *auditor) *tenantServer {
+	t := &tenantServer{servers: make(map[string]*beerLikesServer), stats: make(map[string]*tenantStats), principals: principals, admins: admins, audit: audit}
+	for _, tenant := range tenants {
+		t.servers[tenant] = newServer(tenant)
+		t.servers[tenant].audit = audit
+		t.stats[tenant] = &tenantStats{}
+	}
+	return t
+}
+
+// tenant returns the server of the tenant of a call.
+func (t *tenantServer) tenant(ctx context.Context) (*beerLikesServer, error) {
+	name := tenantName(ctx)
+	s, ok := t.servers[name]
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("unknown tenant %q", name))
+	}
+	return s, nil
+}
+
+// allowed reports whether the principal of a call may use a tenant.
+func (t *tenantServer) allowed(ctx context.Context, name string) bool {
+	if tenant, ok := t.principals[verifiedPrincipal(ctx)]; ok {
+		return name == tenant
+	}
+	return name == defaultTenant
+}
+
+// count rejects a call of an unknown tenant or of a tenant its principal may
+// not use, tags its log entry with the tenant and counts it for the tenant.
+func (t *tenantServer) count(ctx context.Context, call func() error) error {
+	name := tenantName(ctx)
+	stats, ok := t.stats[name]
+	if !ok {
+		return status.Error(codes.PermissionDenied, fmt.Sprintf("unknown tenant %q", name))
+	}
+	if !t.allowed(ctx, name) {
+		return status.Error(codes.PermissionDenied, fmt.Sprintf("%s may not use tenant %q", principal(ctx), name))
+	}
+	grpc_ctxtags.Extract(ctx).Set("tenant", name)
+	atomic.AddUint64(&stats.requests, 1)
+	err := call()
+	if err != nil {
+		atomic.AddUint64(&stats.errors, 1)
+	}
+	return err
+}
+
+func (t *tenantServer) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	var resp interface{}
+	err := t.count(ctx, func() (err error) {
+		resp, err = handler(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+func (t *tenantServer) streamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return t.count(stream.Context(), func() error {
+		return handler(srv, stream)
+	})
+}
+
+// ListTenants streams the counts of likes and requests of each tenant to the
+// admins, and of the tenant of the call to everyone else.
+func (t *tenantServer) ListTenants(query *pb.TenantsQuery, stream pb.BeerLikesAdmin_ListTenantsServer) error {
+	ctx := stream.Context()
+	admin := t.admins[verifiedPrincipal(ctx)]
+	var names []string
+	for name := range t.servers {
+		if !admin && name != tenantName(ctx) {
+			continue
+		}
+		if query.Name == "" || query.Name == name {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return status.Error(codes.NotFound, fmt.Sprintf("tenant %q was not found", query.Name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := t.servers[name]
+		stats := &pb.TenantStats{
+			Tenant:   name,
+			Requests: atomic.LoadUint64(&t.stats[name].requests),
+			Errors:   atomic.LoadUint64(&t.stats[name].errors),
+		}
+		s.mu.RLock()
+		for _, like := range s.savedLikes {
+			if like.DeletedAt == nil {
+				stats.Likes++
+			} else {
+				stats.DeletedLikes++
+			}
+		}
+		stats.RefTypes = int32(len(s.index.byRefType))
+		s.mu.RUnlock()
+		if err := stream.Send(stats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *tenantServer) GetLike(ctx context.Context, query *pb.LikeQuery) (*pb.Like, error) {
+	s, err := t.tenant(ctx)
+	if err != nil {
+		return &pb.Like{}, err
+	}
+	return s.GetLike(ctx, query)
+}
+
+func (t *tenantServer) ListLikes(query *pb.LikesQuery, stream pb.BeerLikes_ListLikesServer) error {
+	s, err := t.tenant(stream.Context())
+	if err != nil {
+		return err
+	}
+	return s.ListLikes(query, stream)
+}
+
+func (t *tenantServer) GetLikesSummary(ctx context.Context, query *pb.LikesQuery) (*pb.LikesSummary, error) {
+	s, err := t.tenant(ctx)
+	if err != nil {
+		return &pb.LikesSummary{}, err
+	}
+	return s.GetLikesSummary(ctx, query)
+}
+
+func (t *tenantServer) RankLikes(query *pb.RankQuery, stream pb.BeerLikes_RankLikesServer) error {
+	s, err := t.tenant(stream.Context())
+	if err != nil {
+		return err
+	}
+	return s.RankLikes(query, stream)
+}
+
+func (t *tenantServer) GetLikesHistogram(ctx context.Context, query *pb.HistogramQuery) (*pb.LikesHistogram, error) {
+	s, err := t.tenant(ctx)
+	if err != nil {
+		return &pb.LikesHistogram{}, err
+	}
+	return s.GetLikesHistogram(ctx, query)
+}
+
+func (t *tenantServer) CreateLike(ctx context.Context, like *pb.Like) (*pb.Like, error) {
+	s, err := t.tenant(ctx)
+	if err != nil {
+		return &pb.Like{}, err
+	}
+	return s.CreateLike(ctx, like)
+}
+
+func (t *tenantServer) DeleteLike(ctx context.Context, query *pb.LikeQuery) (*pb.Like, error) {
+	s, err := t.tenant(ctx)
+	if err != nil {
+		return &pb.Like{}, err
+	}
+	return s.DeleteLike(ctx, query)
+}
+
+func (t *tenantServer) UndeleteLike(ctx context.Context, query *pb.LikeQuery) (*pb.Like, error) {
+	s, err := t.tenant(ctx)
+	if err != nil {
+		return &pb.Like{}, err
+	}
+	return s.UndeleteLike(ctx, query)
+}
+
+func (t *tenantServer) UpdateLike(ctx context.Context, req *pb.UpdateLikeRequest) (*pb.Like, error) {
+	s, err := t.tenant(ctx)
+	if err != nil {
+		return &pb.Like{}, err
+	}
+	return s.UpdateLike(ctx, req)
+}
+
+func (t *tenantServer) ToggleLike(ctx context.Context, req *pb.ToggleLikeRequest) (*pb.ToggleLikeResponse, error) {
+	s, err := t.tenant(ctx)
+	if err != nil {
+		return &pb.ToggleLikeResponse{}, err
+	}
+	return s.ToggleLike(ctx, req)
+}
+
+func (t *tenantServer) WatchLikes(query *pb.LikesQuery, stream pb.BeerLikes_WatchLikesServer) error {
+	s, err := t.tenant(stream.Context())
+	if err != nil {
+		return err
+	}
+	return s.WatchLikes(query, stream)
+}
+
+func (t *tenantServer) ExportLikes(req *pb.ExportRequest, stream pb.BeerLikesAdmin_ExportLikesServer) error {
+	s, err := t.tenant(stream.Context())
+	if err != nil {
+		return err
+	}
+	return s.ExportLikes(req, stream)
+}
+
+func (t *tenantServer) ImportLikes(stream pb.BeerLikesAdmin_ImportLikesServer) error {
+	s, err := t.tenant(stream.Context())
+	if err != nil {
+		return err
+	}
+	return s.ImportLikes(stream)
+}
+
+func (t *tenantServer) SetRefTypeParent(ctx context.Context, req *pb.RefTypeParent) (*pb.RefTypeParent, error) {
+	s, err := t.tenant(ctx)
+	if err != nil {
+		return &pb.RefTypeParent{}, err
+	}
+	return s.SetRefTypeParent(ctx, req)
+}
+
+func (t *tenantServer) ListRefTypeParents(query *pb.RefTypeParentsQuery, stream pb.BeerLikesAdmin_ListRefTypeParentsServer) error {
+	s, err := t.tenant(stream.Context())
+	if err != nil {
+		return err
+	}
+	return s.ListRefTypeParents(query, stream)
+}
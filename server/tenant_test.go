@@ -0,0 +1,165 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// newTestTenantServer returns a tenant server of the default and wine
+// tenants, where alice may use wine and root is an admin.
+func newTestTenantServer() *tenantServer {
+	t := &tenantServer{
+		servers:    make(map[string]*beerLikesServer),
+		stats:      make(map[string]*tenantStats),
+		principals: map[string]string{"alice": "wine"},
+		admins:     map[string]bool{"root": true},
+	}
+	for _, tenant := range []string{defaultTenant, "wine"} {
+		t.servers[tenant] = newTestServer()
+		t.servers[tenant].tenant = tenant
+		t.stats[tenant] = &tenantStats{}
+	}
+	return t
+}
+
+// callContext returns the context of a call with a verified client
+// certificate of principal, unless it is empty, and the tenant and other
+// metadata, given as key value pairs.
+func callContext(principal string, kv ...string) context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(kv...))
+	if principal == "" {
+		return ctx
+	}
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: principal}}
+	info := credentials.TLSInfo{}
+	info.State.VerifiedChains = [][]*x509.Certificate{{cert}}
+	return peer.NewContext(ctx, &peer.Peer{AuthInfo: info})
+}
+
+func (t *tenantServer) testCreate(ctx context.Context, like *pb.Like) (*pb.Like, error) {
+	resp, err := t.unaryInterceptor(ctx, like, &grpc.UnaryServerInfo{FullMethod: "/beerlikes.BeerLikes/CreateLike"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return t.CreateLike(ctx, req.(*pb.Like))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.Like), nil
+}
+
+func (t *tenantServer) testGet(ctx context.Context, id string) (*pb.Like, error) {
+	resp, err := t.unaryInterceptor(ctx, &pb.LikeQuery{Id: id}, &grpc.UnaryServerInfo{FullMethod: "/beerlikes.BeerLikes/GetLike"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return t.GetLike(ctx, req.(*pb.LikeQuery))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.Like), nil
+}
+
+func TestTenantAccess(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"principal of the tenant", callContext("alice", tenantHeader, "wine"), codes.OK},
+		{"principal of a tenant in the default tenant", callContext("alice"), codes.PermissionDenied},
+		{"anonymous in the default tenant", callContext(""), codes.OK},
+		{"anonymous in a tenant", callContext("", tenantHeader, "wine"), codes.PermissionDenied},
+		{"principal without a tenant in a tenant", callContext("bob", tenantHeader, "wine"), codes.PermissionDenied},
+		{"admin in a tenant", callContext("root", tenantHeader, "wine"), codes.PermissionDenied},
+		{"unverified principal header in a tenant", callContext("", tenantHeader, "wine", principalHeader, "alice"), codes.PermissionDenied},
+		{"unknown tenant", callContext("alice", tenantHeader, "nope"), codes.PermissionDenied},
+	}
+	for _, test := range tests {
+		ts := newTestTenantServer()
+		_, err := ts.testCreate(test.ctx, &pb.Like{RefType: &pb.RefType{Name: "beer", Id: "1"}, Liked: true})
+		if code := status.Code(err); code != test.want {
+			t.Errorf("%s: CreateLike returned %v, want %v", test.name, err, test.want)
+		}
+	}
+}
+
+func TestTenantIsolation(t *testing.T) {
+	ts := newTestTenantServer()
+	wine := callContext("alice", tenantHeader, "wine")
+	like, err := ts.testCreate(wine, &pb.Like{RefType: &pb.RefType{Name: "wine", Id: "9"}, Liked: true})
+	if err != nil {
+		t.Fatalf("CreateLike in wine: %v", err)
+	}
+	if _, err := ts.testGet(wine, like.Id); err != nil {
+		t.Errorf("GetLike in wine: %v", err)
+	}
+	if _, err := ts.testGet(callContext(""), like.Id); status.Code(err) != codes.NotFound {
+		t.Errorf("GetLike of a wine like in the default tenant returned %v, want NotFound", err)
+	}
+	if n := len(ts.servers[defaultTenant].savedLikes); n != 0 {
+		t.Errorf("the default tenant has %d likes, want 0", n)
+	}
+}
+
+// tenantsStream collects the stats sent by ListTenants.
+type tenantsStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []string
+}
+
+func (s *tenantsStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *tenantsStream) Send(stats *pb.TenantStats) error {
+	s.sent = append(s.sent, stats.Tenant)
+	return nil
+}
+
+func TestListTenantsVisibility(t *testing.T) {
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		query string
+		want  []string
+		code  codes.Code
+	}{
+		{"anonymous", callContext(""), "", []string{defaultTenant}, codes.OK},
+		{"principal of a tenant", callContext("alice", tenantHeader, "wine"), "", []string{"wine"}, codes.OK},
+		{"another tenant by name", callContext(""), "wine", nil, codes.NotFound},
+		{"admin", callContext("root"), "", []string{defaultTenant, "wine"}, codes.OK},
+	}
+	for _, test := range tests {
+		ts := newTestTenantServer()
+		stream := &tenantsStream{ctx: test.ctx}
+		err := ts.ListTenants(&pb.TenantsQuery{Name: test.query}, stream)
+		if code := status.Code(err); code != test.code {
+			t.Errorf("%s: ListTenants returned %v, want %v", test.name, err, test.code)
+		}
+		if len(stream.sent) != len(test.want) {
+			t.Errorf("%s: ListTenants sent %v, want %v", test.name, stream.sent, test.want)
+			continue
+		}
+		for i := range test.want {
+			if stream.sent[i] != test.want[i] {
+				t.Errorf("%s: ListTenants sent %v, want %v", test.name, stream.sent, test.want)
+				break
+			}
+		}
+	}
+}
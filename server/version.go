@@ -0,0 +1,93 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// versionHeader is the response header with the version of the likes of the
+// RefType a call read or changed.
+const versionHeader = "x-likes-version"
+
+// versionMetadata returns the response header for a version.
+func versionMetadata(version uint64) metadata.MD {
+	return metadata.Pairs(versionHeader, strconv.FormatUint(version, 10))
+}
+
+// versions tracks the version of the likes of each RefType: the value of a
+// server wide counter at their last change. The counter starts at the Unix
+// time in nanoseconds, so versions keep increasing across restarts.
+type versions struct {
+	start     uint64
+	last      uint64
+	byRefType map[string]uint64
+}
+
+func newVersions() *versions {
+	start := uint64(time.Now().UnixNano())
+	return &versions{start: start, last: start, byRefType: make(map[string]uint64)}
+}
+
+// bump records a change to the likes of a RefType.
+func (v *versions) bump(refType *pb.RefType) {
+	v.last++
+	v.byRefType[refTypeKey(refType)] = v.last
+}
+
+// get returns the version of the likes of a RefType.
+func (v *versions) get(refType *pb.RefType) uint64 {
+	if version, ok := v.byRefType[refTypeKey(refType)]; ok {
+		return version
+	}
+	return v.start
+}
+
+// summaryCache memoizes the summary of each RefType. An entry is only used
+// while its version is the current version of the RefType, so writes
+// invalidate it without touching the cache.
+type summaryCache struct {
+	mu      sync.Mutex
+	entries map[string]*pb.LikesSummary
+}
+
+func newSummaryCache() *summaryCache {
+	return &summaryCache{entries: make(map[string]*pb.LikesSummary)}
+}
+
+// get returns the cached summary of a RefType at the given version, or nil.
+func (c *summaryCache) get(refType *pb.RefType, version uint64) *pb.LikesSummary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if summary, ok := c.entries[refTypeKey(refType)]; ok && summary.Version == version {
+		return summary
+	}
+	return nil
+}
+
+// put caches a summary unless a newer one is cached already.
+func (c *summaryCache) put(summary *pb.LikesSummary) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := refTypeKey(summary.RefType)
+	if cached, ok := c.entries[key]; !ok || cached.Version < summary.Version {
+		c.entries[key] = summary
+	}
+}
+
+// remove drops the summary of a RefType.
+func (c *summaryCache) remove(refType *pb.RefType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, refTypeKey(refType))
+}
@@ -0,0 +1,90 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+func TestWritesBumpTheVersionsOfTheRefTypeAndItsAncestors(t *testing.T) {
+	s := newTestServer()
+	ctx := context.Background()
+	review := &pb.RefType{Name: "review", Id: "1"}
+	beer := &pb.RefType{Name: "beer", Id: "1"}
+	brewery := &pb.RefType{Name: "brewery", Id: "1"}
+	other := &pb.RefType{Name: "beer", Id: "2"}
+	for _, link := range []*pb.RefTypeParent{{RefType: review, Parent: beer}, {RefType: beer, Parent: brewery}} {
+		if _, err := s.SetRefTypeParent(ctx, link); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := map[*pb.RefType]uint64{}
+	for _, refType := range []*pb.RefType{review, beer, brewery, other} {
+		before[refType] = s.versions.get(refType)
+	}
+	like, err := s.CreateLike(ctx, &pb.Like{RefType: review, Liked: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, refType := range []*pb.RefType{review, beer, brewery} {
+		if version := s.versions.get(refType); version <= before[refType] {
+			t.Errorf("CreateLike left the version of %v at %d", refType, version)
+		}
+		before[refType] = s.versions.get(refType)
+	}
+	if version := s.versions.get(other); version != before[other] {
+		t.Errorf("CreateLike bumped the version of the unrelated %v from %d to %d", other, before[other], version)
+	}
+
+	if _, err := s.DeleteLike(ctx, &pb.LikeQuery{Id: like.Id}); err != nil {
+		t.Fatal(err)
+	}
+	for _, refType := range []*pb.RefType{review, beer, brewery} {
+		if version := s.versions.get(refType); version <= before[refType] {
+			t.Errorf("DeleteLike left the version of %v at %d", refType, version)
+		}
+	}
+}
+
+func TestGetLikesSummaryIfVersionChanged(t *testing.T) {
+	s := newTestServer()
+	ctx := context.Background()
+	beer := &pb.RefType{Name: "beer", Id: "1"}
+	if _, err := s.CreateLike(ctx, &pb.Like{RefType: beer, Liked: true}); err != nil {
+		t.Fatal(err)
+	}
+	summary, err := s.GetLikesSummary(ctx, &pb.LikesQuery{RefType: beer})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := s.GetLikesSummary(ctx, &pb.LikesQuery{RefType: beer, IfVersionChanged: summary.Version})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !current.NotModified || len(current.Likes) != 0 || current.Version != summary.Version {
+		t.Errorf("the summary at the current version %d is %v, want it not_modified without the likes", summary.Version, current)
+	}
+
+	if _, err := s.CreateLike(ctx, &pb.Like{RefType: beer, Liked: false}); err != nil {
+		t.Fatal(err)
+	}
+	stale, err := s.GetLikesSummary(ctx, &pb.LikesQuery{RefType: beer, IfVersionChanged: summary.Version})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stale.NotModified || len(stale.Likes) != 2 || stale.LikeCount != 1 || stale.DislikeCount != 1 {
+		t.Errorf("the summary at the stale version %d is %v, want the full summary of 2 likes", summary.Version, stale)
+	}
+	if stale.Version <= summary.Version {
+		t.Errorf("the summary after a write has version %d, want it after %d", stale.Version, summary.Version)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ *
+ * Copyright 2018 Chris Page <phriscage@gmail.com>
+ *
+ */
+
+package main
+
+import (
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/phriscage/beer-likes/beerlikes"
+)
+
+// watchBuffer is the number of events a watcher can fall behind by before it
+// is dropped.
+const watchBuffer = 256
+
+// watchers fans the recorded events out to the WatchLikes streams.
+type watchers struct {
+	mu   sync.Mutex
+	next int
+	subs map[int]chan *pb.LikeEvent
+}
+
+func newWatchers() *watchers {
+	return &watchers{subs: make(map[int]chan *pb.LikeEvent)}
+}
+
+// subscribe returns the id and the channel of a new watcher.
+func (w *watchers) subscribe() (int, <-chan *pb.LikeEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.next++
+	ch := make(chan *pb.LikeEvent, watchBuffer)
+	w.subs[w.next] = ch
+	return w.next, ch
+}
+
+// unsubscribe removes a watcher and closes its channel, unless it was
+// already dropped.
+func (w *watchers) unsubscribe(id int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if ch, ok := w.subs[id]; ok {
+		delete(w.subs, id)
+		close(ch)
+	}
+}
+
+// publish sends an event to every watcher without blocking. A watcher whose
+// buffer is full is dropped by closing its channel.
+func (w *watchers) publish(event *pb.LikeEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for id, ch := range w.subs {
+		select {
+		case ch <- event:
+		default:
+			delete(w.subs, id)
+			close(ch)
+		}
+	}
+}
+
+// WatchLikes streams the like events of the queried RefTypes as they are
+// recorded, with only the like fields of the read mask.
+func (s *beerLikesServer) WatchLikes(query *pb.LikesQuery, stream pb.BeerLikes_WatchLikesServer) error {
+	if query.RefType != nil {
+		if err := validateLikesQuery(query); err != nil {
+			return err
+		}
+	}
+	if err := validateMask("read_mask", query.ReadMask, readPaths); err != nil {
+		return err
+	}
+	id, events := s.watchers.subscribe()
+	defer s.watchers.unsubscribe(id)
+	ctx := stream.Context()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "the watcher fell too far behind")
+			}
+			// A like moved to another RefType is an event of both.
+			if query.RefType != nil && !matchesRefType(query, event.GetLike().GetRefType()) && (event.PreviousRefType == nil || !matchesRefType(query, event.PreviousRefType)) {
+				continue
+			}
+			if len(query.ReadMask.GetPaths()) > 0 {
+				// The event is shared by the watchers, so the like is masked on a copy.
+				masked := *event
+				masked.Like = maskLike(event.Like, query.ReadMask)
+				event = &masked
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}